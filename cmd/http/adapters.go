@@ -41,7 +41,7 @@ func newAdapters(ctx context.Context, config configuration, clients clients) (ad
 		return output, fmt.Errorf("renderer: %w", err)
 	}
 
-	output.hello = cache.New(clients.redis, func(id string) string { return id }, func(_ context.Context, id string) (string, error) { return hash.String(id), nil }, clients.telemetry.TracerProvider()).
+	output.hello = cache.New("hello", clients.redis, func(id string) string { return id }, func(_ context.Context, id string) (string, error) { return hash.String(id), nil }, clients.telemetry.MeterProvider(), clients.telemetry.TracerProvider()).
 		WithTTL(time.Hour).
 		WithExtendOnHit(ctx, 10*time.Second, 50).
 		WithClientSideCaching(ctx, "httputils_hello", 10)
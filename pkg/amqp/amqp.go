@@ -9,6 +9,7 @@ import (
 	"io"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/ViBiOh/flags"
 	"github.com/ViBiOh/httputils/v4/pkg/recoverer"
@@ -38,16 +39,22 @@ type Client struct {
 	messageMetric   metric.Int64Counter
 	channel         *amqp.Channel
 	listeners       map[string]*listener
+	returns         chan amqp.Return
 	vhost           string
 	uri             string
 	attributes      []attribute.KeyValue
 	prefetch        int
+	confirmTimeout  time.Duration
 	mutex           sync.RWMutex
+	confirmMutex    sync.Mutex
+	confirm         bool
 }
 
 type Config struct {
-	URI      string
-	Prefetch int
+	URI            string
+	Prefetch       int
+	ConfirmTimeout time.Duration
+	Confirm        bool
 }
 
 func Flags(fs *flag.FlagSet, prefix string, overrides ...flags.Override) *Config {
@@ -55,23 +62,23 @@ func Flags(fs *flag.FlagSet, prefix string, overrides ...flags.Override) *Config
 
 	flags.New("URI", "Address in the form amqps?://<user>:<password>@<address>:<port>/<vhost>").Prefix(prefix).DocPrefix("amqp").StringVar(fs, &config.URI, "", overrides)
 	flags.New("Prefetch", "Prefetch count for QoS").Prefix(prefix).DocPrefix("amqp").IntVar(fs, &config.Prefetch, 1, overrides)
+	flags.New("Confirm", "Wait for the broker confirmation of published messages, unroutable ones being returned as error").Prefix(prefix).DocPrefix("amqp").BoolVar(fs, &config.Confirm, false, overrides)
+	flags.New("ConfirmTimeout", "Timeout for the broker confirmation of a published message, 0 for no timeout").Prefix(prefix).DocPrefix("amqp").DurationVar(fs, &config.ConfirmTimeout, 5*time.Second, overrides)
 
 	return &config
 }
 
 func New(ctx context.Context, config *Config, meterProvider metric.MeterProvider, tracerProvider trace.TracerProvider) (*Client, error) {
-	return NewFromURI(ctx, config.URI, config.Prefetch, meterProvider, tracerProvider)
-}
-
-func NewFromURI(ctx context.Context, uri string, prefetch int, meterProvider metric.MeterProvider, tracerProvider trace.TracerProvider) (*Client, error) {
-	if len(uri) == 0 {
+	if len(config.URI) == 0 {
 		return nil, ErrNoConfig
 	}
 
 	client := &Client{
-		uri:       uri,
-		prefetch:  prefetch,
-		listeners: make(map[string]*listener),
+		uri:            config.URI,
+		prefetch:       config.Prefetch,
+		confirm:        config.Confirm,
+		confirmTimeout: config.ConfirmTimeout,
+		listeners:      make(map[string]*listener),
 	}
 
 	if meterProvider != nil {
@@ -92,13 +99,13 @@ func NewFromURI(ctx context.Context, uri string, prefetch int, meterProvider met
 		}
 	}
 
-	connection, channel, err := connect(uri, client.prefetch, client.onDisconnect)
+	connection, channel, err := connect(client.uri, client.prefetch, client.confirm, client.onDisconnect)
 	if err != nil {
 		return nil, fmt.Errorf("connect to amqp: %w", err)
 	}
 
 	client.connection = connection
-	client.channel = channel
+	client.setChannel(channel)
 	client.vhost = connection.Config.Vhost
 
 	slog.LogAttrs(ctx, slog.LevelInfo, "Connected to AMQP!", slog.String("vhost", client.vhost))
@@ -110,6 +117,10 @@ func NewFromURI(ctx context.Context, uri string, prefetch int, meterProvider met
 	return client, nil
 }
 
+func NewFromURI(ctx context.Context, uri string, prefetch int, meterProvider metric.MeterProvider, tracerProvider trace.TracerProvider) (*Client, error) {
+	return New(ctx, &Config{URI: uri, Prefetch: prefetch}, meterProvider, tracerProvider)
+}
+
 func initMetrics(provider metric.MeterProvider) (metric.Int64Counter, metric.Int64UpDownCounter, metric.Int64Counter, error) {
 	meter := provider.Meter("github.com/ViBiOh/httputils/v4/pkg/amqp")
 
@@ -154,9 +165,17 @@ func (c *Client) Publish(ctx context.Context, payload amqp.Publishing, exchange,
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	if err = c.channel.PublishWithContext(ctx, exchange, routingKey, false, false, telemetry.InjectToAmqp(ctx, payload)); err != nil {
+	payload = telemetry.InjectToAmqp(ctx, payload)
+
+	if c.confirm {
+		err = c.publishConfirmed(ctx, payload, exchange, routingKey)
+	} else {
+		err = c.channel.PublishWithContext(ctx, exchange, routingKey, false, false, payload)
+	}
+
+	if err != nil {
 		c.increase(ctx, append([]attribute.KeyValue{
-			semconv.ErrorTypeKey.String("amqp:publish"),
+			semconv.ErrorTypeKey.String(publishErrorType(err)),
 		}, attributes...))
 
 		return err
@@ -34,13 +34,13 @@ func (c *Client) reconnect(ctx context.Context) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	newConnection, newChannel, err := connect(c.uri, c.prefetch, c.onDisconnect)
+	newConnection, newChannel, err := connect(c.uri, c.prefetch, c.confirm, c.onDisconnect)
 	if err != nil {
 		return fmt.Errorf("reconnect to amqp: %w", err)
 	}
 
 	c.connection = newConnection
-	c.channel = newChannel
+	c.setChannel(newChannel)
 	c.vhost = newConnection.Config.Vhost
 
 	slog.InfoContext(ctx, "Connection reopened.")
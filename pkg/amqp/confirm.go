@@ -0,0 +1,118 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// returnsSize bounds the returns buffered by the channel, the connection blocking when it's full.
+const returnsSize = 8
+
+var (
+	ErrNack           = errors.New("message nacked by the broker")
+	ErrConfirmTimeout = errors.New("broker confirmation timeout")
+)
+
+// ReturnError is the error of a message returned by the broker because it's unroutable.
+type ReturnError struct {
+	Exchange   string
+	RoutingKey string
+	ReplyText  string
+	ReplyCode  uint16
+}
+
+func (r *ReturnError) Error() string {
+	return fmt.Sprintf("message returned from exchange `%s` with routing key `%s`: %d %s", r.Exchange, r.RoutingKey, r.ReplyCode, r.ReplyText)
+}
+
+// setChannel replaces the publishing channel, listening for its returns in confirm mode. The mutex has to be held.
+func (c *Client) setChannel(channel *amqp.Channel) {
+	c.channel = channel
+
+	if c.confirm {
+		c.returns = channel.NotifyReturn(make(chan amqp.Return, returnsSize))
+	}
+}
+
+// publishConfirmed publishes a mandatory message and waits for the broker confirmation. The broker sends the return of an unroutable message before its ack, so publications are serialized for attributing a return to its message.
+func (c *Client) publishConfirmed(ctx context.Context, payload amqp.Publishing, exchange, routingKey string) error {
+	c.confirmMutex.Lock()
+	defer c.confirmMutex.Unlock()
+
+	c.drainReturns(ctx)
+
+	confirmation, err := c.channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, true, false, payload)
+	if err != nil {
+		return err
+	}
+
+	if c.confirmTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeoutCause(ctx, c.confirmTimeout, ErrConfirmTimeout)
+		defer cancel()
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, ErrConfirmTimeout) {
+			return cause
+		}
+
+		return fmt.Errorf("wait confirmation: %w", err)
+	}
+
+	if !acked {
+		return ErrNack
+	}
+
+	select {
+	case returned, ok := <-c.returns:
+		if ok {
+			return &ReturnError{
+				Exchange:   returned.Exchange,
+				RoutingKey: returned.RoutingKey,
+				ReplyCode:  returned.ReplyCode,
+				ReplyText:  returned.ReplyText,
+			}
+		}
+	default:
+	}
+
+	return nil
+}
+
+// drainReturns discards the returns of previous messages, whose confirmation timed out.
+func (c *Client) drainReturns(ctx context.Context) {
+	for {
+		select {
+		case returned, ok := <-c.returns:
+			if !ok {
+				return
+			}
+
+			slog.LogAttrs(ctx, slog.LevelWarn, "discard stale return", slog.String("exchange", returned.Exchange), slog.String("routing_key", returned.RoutingKey))
+		default:
+			return
+		}
+	}
+}
+
+func publishErrorType(err error) string {
+	var returnErr *ReturnError
+
+	switch {
+	case errors.As(err, &returnErr):
+		return "amqp:return"
+	case errors.Is(err, ErrNack):
+		return "amqp:nack"
+	case errors.Is(err, ErrConfirmTimeout):
+		return "amqp:confirm_timeout"
+	default:
+		return "amqp:publish"
+	}
+}
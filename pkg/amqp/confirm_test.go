@@ -0,0 +1,43 @@
+package amqp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPublishErrorType(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"publish": {
+			errors.New("channel closed"),
+			"amqp:publish",
+		},
+		"return": {
+			fmt.Errorf("publish: %w", &ReturnError{Exchange: "httputils", RoutingKey: "unknown", ReplyCode: 312, ReplyText: "NO_ROUTE"}),
+			"amqp:return",
+		},
+		"nack": {
+			ErrNack,
+			"amqp:nack",
+		},
+		"timeout": {
+			fmt.Errorf("publish: %w", ErrConfirmTimeout),
+			"amqp:confirm_timeout",
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			if got := publishErrorType(testCase.err); got != testCase.want {
+				t.Errorf("publishErrorType() = `%s`, want `%s`", got, testCase.want)
+			}
+		})
+	}
+}
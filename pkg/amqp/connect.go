@@ -10,7 +10,7 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-func connect(uri string, prefetch int, onDisconnect func(context.Context, *slog.Logger)) (*amqp.Connection, *amqp.Channel, error) {
+func connect(uri string, prefetch int, confirm bool, onDisconnect func(context.Context, *slog.Logger)) (*amqp.Connection, *amqp.Channel, error) {
 	slog.Info("Dialing AMQP with 10 seconds timeout...")
 
 	connection, err := amqp.DialConfig(uri, amqp.Config{
@@ -23,6 +23,12 @@ func connect(uri string, prefetch int, onDisconnect func(context.Context, *slog.
 	}
 
 	channel, err := createChannel(connection, prefetch)
+	if err == nil && confirm {
+		if err = channel.Confirm(false); err != nil {
+			err = closeChannel(fmt.Errorf("enable confirm mode: %w", err), channel)
+		}
+	}
+
 	if err != nil {
 		err := fmt.Errorf("create channel: %w", err)
 
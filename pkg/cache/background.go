@@ -16,3 +16,18 @@ func doInBackground(ctx context.Context, callback func(ctx context.Context) erro
 		slog.LogAttrs(ctx, slog.LevelError, "background callback", slog.Any("error", err))
 	}
 }
+
+// background runs the action in the write queue when there is one, in a new goroutine otherwise. Pending actions of the same key are coalesced by the queue, the key being prefixed by the cache name as the queue can be shared.
+func (c *Cache[K, V]) background(ctx context.Context, key string, action func(ctx context.Context) error) {
+	if c.queue != nil {
+		if len(key) != 0 {
+			key = c.name + " " + key
+		}
+
+		c.queue.Enqueue(ctx, key, action)
+
+		return
+	}
+
+	go doInBackground(context.WithoutCancel(ctx), action)
+}
@@ -2,13 +2,18 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
 	"github.com/ViBiOh/httputils/v4/pkg/cache/memory"
+	"github.com/ViBiOh/httputils/v4/pkg/concurrent"
 	"github.com/ViBiOh/httputils/v4/pkg/model"
+	httpredis "github.com/ViBiOh/httputils/v4/pkg/redis"
 	"github.com/ViBiOh/httputils/v4/pkg/telemetry"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -16,6 +21,9 @@ import (
 
 var syncActionTimeout = time.Millisecond * 150
 
+// defaultFetchTimeout bounds a call of onMiss shared between callers, it doesn't stop when they go away.
+const defaultFetchTimeout = time.Minute
+
 type RedisClient interface {
 	Enabled() bool
 	Load(ctx context.Context, key string) ([]byte, error)
@@ -23,43 +31,79 @@ type RedisClient interface {
 	Store(ctx context.Context, key string, value any, ttl time.Duration) error
 	StoreMany(ctx context.Context, values map[string]any, ttl time.Duration) error
 	Delete(ctx context.Context, keys ...string) error
+	Tag(ctx context.Context, tagged map[string][]string, ttl time.Duration) error
+	DeleteTag(ctx context.Context, tag string) error
 	Expire(ctx context.Context, ttl time.Duration, keys ...string) error
+	TTL(ctx context.Context, key string) (time.Duration, bool, error)
+	Scan(ctx context.Context, pattern string, output chan<- string, pageSize int64) error
+	Exclusive(ctx context.Context, name string, timeout time.Duration, action func(context.Context) error) (bool, error)
 	Pipeline() redis.Pipeliner
 
 	PublishJSON(ctx context.Context, channel string, value any) error
-	Subscribe(ctx context.Context, channel string) (<-chan *redis.Message, func(context.Context))
+	Subscribe(ctx context.Context, channel string, options ...httpredis.SubscribeOption) (<-chan *redis.Message, func(context.Context))
 }
 
 type (
 	keyer[K comparable]            func(K) string
 	fetch[K comparable, V any]     func(context.Context, K) (V, error)
 	fetchMany[K comparable, V any] func(context.Context, []K) ([]V, error)
+	tagger[K comparable, V any]    func(K, V) []string
+	ttler[V any]                   func(V) time.Duration
 )
 
 type Cache[K comparable, V any] struct {
-	serializer  Serializer[V]
-	read        RedisClient
-	write       RedisClient
-	tracer      trace.Tracer
-	toKey       keyer[K]
-	onMiss      fetch[K, V]
-	onMissMany  fetchMany[K, V]
-	memory      *memory.Cache[K, V]
-	extender    *TTLExtender
-	channel     string
-	ttl         time.Duration
-	concurrency int
-}
-
-func New[K comparable, V any](client RedisClient, toKey keyer[K], onMiss fetch[K, V], tracerProvider trace.TracerProvider) *Cache[K, V] {
+	serializer   Serializer[V]
+	read         RedisClient
+	write        RedisClient
+	tracer       trace.Tracer
+	metrics      *metrics
+	toKey        keyer[K]
+	tagger       tagger[K, V]
+	ttlFunc      ttler[V]
+	parseID      func(string) (K, error)
+	onMiss       fetch[K, V]
+	onMissMany   fetchMany[K, V]
+	memory       *memory.Cache[K, V]
+	extender     *TTLExtender
+	queue        *WriteQueue
+	inflight     *concurrent.SingleFlight[string, V]
+	done         chan struct{}
+	name         string
+	channel      string
+	ttl          time.Duration
+	softTTL      time.Duration
+	staleIfError time.Duration
+	negativeTTL  time.Duration
+	version      uint32
+	lockTTL      time.Duration
+	lockWait     time.Duration
+	concurrency  int
+}
+
+// New creates a cache, the name is used as an attribute of the metrics.
+func New[K comparable, V any](name string, client RedisClient, toKey keyer[K], onMiss fetch[K, V], meterProvider metric.MeterProvider, tracerProvider trace.TracerProvider) *Cache[K, V] {
 	client = getClient(client)
 
+	done := make(chan struct{})
+	close(done)
+
 	cache := &Cache[K, V]{
+		done:       done,
+		name:       name,
 		read:       client,
 		write:      client,
 		toKey:      toKey,
 		serializer: JSONSerializer[V]{},
 		onMiss:     onMiss,
+		inflight:   concurrent.NewSingleFlight[string, V](defaultFetchTimeout),
+	}
+
+	if meterProvider != nil {
+		var err error
+
+		if cache.metrics, err = newMetrics(meterProvider, name); err != nil {
+			slog.LogAttrs(context.Background(), slog.LevelError, "init cache metrics", slog.String("name", name), slog.Any("error", err))
+		}
 	}
 
 	if tracerProvider != nil {
@@ -93,25 +137,82 @@ func (c *Cache[K, V]) WithTTL(ttl time.Duration) *Cache[K, V] {
 	return c
 }
 
+// WithTTLFunc computes the TTL of each value, e.g. from an upstream expiry. A zero duration falls back to the TTL of the cache, a negative one doesn't store the value. Values with their own TTL are not extended on hit.
+func (c *Cache[K, V]) WithTTLFunc(cb ttler[V]) *Cache[K, V] {
+	c.ttlFunc = cb
+
+	return c
+}
+
+// WithSoftTTL serves values older than softTTL while refreshing them in background. Values older than the TTL are fetched again, but still served during staleIfError when the fetch fails.
+func (c *Cache[K, V]) WithSoftTTL(softTTL, staleIfError time.Duration) *Cache[K, V] {
+	c.softTTL = softTTL
+	c.staleIfError = staleIfError
+
+	return c
+}
+
+// WithNegativeTTL caches errors matching model.ErrNotFound during the given ttl, so missing ids don't reach onMiss on every call.
+func (c *Cache[K, V]) WithNegativeTTL(ttl time.Duration) *Cache[K, V] {
+	c.negativeTTL = ttl
+
+	return c
+}
+
+// WithVersion stores values with the given schema version. Values of another version are considered as missing, and values of a newer version are never overwritten.
+func (c *Cache[K, V]) WithVersion(version uint32) *Cache[K, V] {
+	c.version = version
+
+	return c
+}
+
 func (c *Cache[K, V]) WithExtendOnHit(ctx context.Context, interval time.Duration, maxSize int) *Cache[K, V] {
-	c.extender = NewExtender(c.ttl, interval, maxSize, c.write)
+	c.extender = NewExtender(c.storageTTL(c.ttl), interval, maxSize, c.write)
 
 	go c.extender.Start(ctx)
 
 	return c
 }
 
+// WithWriteQueue does the background stores, refreshes and TTL extensions in the given queue instead of a goroutine per write. The queue has to be started.
+func (c *Cache[K, V]) WithWriteQueue(queue *WriteQueue) *Cache[K, V] {
+	c.queue = queue
+
+	return c
+}
+
+// WithFetchTimeout bounds a call of onMiss or onMissMany shared between concurrent callers, one minute by default. It runs independently of the callers' cancellation, each one stopping to wait when its own context is done.
+func (c *Cache[K, V]) WithFetchTimeout(timeout time.Duration) *Cache[K, V] {
+	c.inflight = concurrent.NewSingleFlight[string, V](timeout)
+
+	return c
+}
+
 func (c *Cache[K, V]) WithMaxConcurrency(concurrency int) *Cache[K, V] {
 	c.concurrency = concurrency
 
 	return c
 }
 
+// WithDistributedLock calls onMiss on a single instance for a missing key, holding a Redis lock during at most ttl. The other instances poll Redis during wait for the fresh value, then fetch it by themselves.
+func (c *Cache[K, V]) WithDistributedLock(ttl, wait time.Duration) *Cache[K, V] {
+	c.lockTTL = ttl
+	c.lockWait = wait
+
+	return c
+}
+
+// WithClientSideCaching keeps up to size values in memory, evicting the least recently used ones. A zero size is unbounded.
 func (c *Cache[K, V]) WithClientSideCaching(ctx context.Context, channel string, size int) *Cache[K, V] {
-	c.memory = memory.New[K, V](size)
+	return c.WithMemoryCache(ctx, channel, memory.New[K, V](size, memory.NewLRU[K]))
+}
+
+// WithMemoryCache keeps values in the given memory cache, e.g. one with another eviction policy. The channel notifies evictions to the other instances.
+func (c *Cache[K, V]) WithMemoryCache(ctx context.Context, channel string, memoryCache *memory.Cache[K, V]) *Cache[K, V] {
+	c.memory = memoryCache
 	c.channel = channel
 
-	go c.subscribe(ctx)
+	go c.subscribe(ctx, c.tagger != nil)
 	go c.memory.Start(ctx)
 
 	return c
@@ -131,12 +232,15 @@ func (c *Cache[K, V]) Get(ctx context.Context, id K) (V, error) {
 	}
 
 	if cached, ok := c.memoryRead(id); ok {
+		c.metrics.recordMemoryHit(ctx, 1)
 		c.extendTTL(ctx, c.toKey(id))
 
 		return cached, nil
 	}
 
 	if c.read == nil {
+		c.metrics.recordMiss(ctx, 1)
+
 		return c.fetch(ctx, id)
 	}
 
@@ -152,15 +256,43 @@ func (c *Cache[K, V]) Get(ctx context.Context, id K) (V, error) {
 
 	if content, err := c.read.Load(loadCtx, key); err != nil {
 		slog.LogAttrs(ctx, slog.LevelError, "load from cache", slog.String("key", key), slog.Any("error", err))
-	} else if value, ok, err := c.decode(content); err != nil {
+	} else if c.isNotFound(content) {
+		var value V
+
+		c.metrics.recordRedisHit(ctx, 1)
+
+		return value, ErrCachedNotFound
+	} else if value, writtenAt, ok, err := c.decode(content); err != nil {
 		logUnmarshalError(ctx, key, err)
+		c.recordDecodeError(ctx, err)
+
+		if errors.Is(err, errNewerVersion) {
+			ctx = skipStore(ctx)
+		}
 	} else if ok {
-		c.memoryWrite(id, value, c.ttl)
-		c.extendTTL(ctx, key)
+		switch c.freshness(writtenAt, c.entryTTL(value)) {
+		case fresh:
+			c.metrics.recordRedisHit(ctx, 1)
+			c.memoryWrite(id, value, c.freshTTL(writtenAt, c.entryTTL(value)))
+			c.extendTTL(ctx, key)
+
+			return value, nil
+
+		case stale:
+			c.metrics.recordRedisHit(ctx, 1)
+			c.refresh(ctx, id)
+
+			return value, nil
+
+		case expired:
+			c.metrics.recordMiss(ctx, 1)
 
-		return value, nil
+			return c.fetchOrStale(ctx, id, value, writtenAt)
+		}
 	}
 
+	c.metrics.recordMiss(ctx, 1)
+
 	return c.fetch(ctx, id)
 }
 
@@ -170,38 +302,116 @@ func (c *Cache[K, V]) fetch(ctx context.Context, id K) (V, error) {
 	ctx, end := telemetry.StartSpan(ctx, c.tracer, "fetch", trace.WithSpanKind(trace.SpanKindInternal))
 	defer end(&err)
 
-	value, err := c.onMiss(ctx, id)
+	value, shared, err := c.inflight.Do(ctx, c.toKey(id), func(ctx context.Context) (V, error) {
+		if value, ok, err := c.fetchExclusive(ctx, id); ok {
+			return value, err
+		}
+
+		value, err := c.callOnMiss(ctx, id)
+
+		if isStoreSkipped(ctx) {
+			return value, err
+		}
+
+		if err == nil {
+			c.background(ctx, c.toKey(id), func(ctx context.Context) error {
+				return c.store(ctx, id, value)
+			})
+		} else if c.shouldStoreNotFound(err) {
+			c.background(ctx, c.toKey(id), func(ctx context.Context) error {
+				return c.storeNotFound(ctx, id)
+			})
+		}
+
+		return value, err
+	})
 
-	if err == nil {
-		go doInBackground(context.WithoutCancel(ctx), func(ctx context.Context) error {
-			return c.store(ctx, id, value)
-		})
+	if shared {
+		c.recordCoalesced(ctx, 1)
 	}
 
 	return value, err
 }
 
-func (c *Cache[K, V]) decode(content []byte) (value V, ok bool, err error) {
+func (c *Cache[K, V]) callOnMiss(ctx context.Context, id K) (V, error) {
+	defer c.metrics.recordFetch(ctx, time.Now())
+
+	return c.onMiss(ctx, id)
+}
+
+func (c *Cache[K, V]) recordCoalesced(ctx context.Context, count int) {
+	c.metrics.recordCoalesced(ctx, count)
+
+	if c.tracer == nil || count == 0 {
+		return
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("cache.coalesced", count))
+}
+
+func (c *Cache[K, V]) decode(content []byte) (value V, writtenAt time.Time, ok bool, err error) {
 	if len(content) == 0 {
-		return value, ok, err
+		return value, writtenAt, ok, err
+	}
+
+	if c.withEnvelope() {
+		item, ok := unwrapEnvelope(content)
+		if !ok {
+			return value, writtenAt, false, errNoEnvelope
+		}
+
+		if err = checkVersion(c.version, item.version); err != nil {
+			return value, writtenAt, ok, err
+		}
+
+		content, writtenAt = item.payload, item.writtenAt
 	}
 
 	value, err = c.serializer.Decode(content)
 	ok = err == nil
 
-	return value, ok, err
+	return value, writtenAt, ok, err
+}
+
+func (c *Cache[K, V]) encode(value V) ([]byte, error) {
+	payload, err := c.serializer.Encode(value)
+	if err != nil || !c.withEnvelope() {
+		return payload, err
+	}
+
+	return wrapEnvelope(c.version, time.Now(), payload), nil
 }
 
 func (c *Cache[K, V]) extendTTL(ctx context.Context, keys ...string) {
-	if c.write == nil || c.extender == nil || c.ttl == 0 || len(keys) == 0 {
+	if c.write == nil || c.extender == nil || c.ttl == 0 || c.ttlFunc != nil || len(keys) == 0 {
 		return
 	}
 
-	go doInBackground(context.WithoutCancel(ctx), func(ctx context.Context) error {
+	var queueKey string
+	if len(keys) == 1 {
+		queueKey = extendQueuePrefix + keys[0]
+	}
+
+	c.background(ctx, queueKey, func(ctx context.Context) error {
 		return c.extender.Extend(ctx, keys...)
 	})
 }
 
+// recordDecodeError doesn't count version mismatches, they are expected during a schema change.
+func (c *Cache[K, V]) recordDecodeError(ctx context.Context, err error) {
+	if errors.Is(err, errVersionMismatch) {
+		return
+	}
+
+	c.metrics.recordDecodeError(ctx)
+}
+
 func logUnmarshalError(ctx context.Context, key string, err error) {
+	if errors.Is(err, errVersionMismatch) {
+		slog.LogAttrs(ctx, slog.LevelDebug, "version mismatch in cache", slog.String("key", key), slog.Any("error", err))
+
+		return
+	}
+
 	slog.LogAttrs(ctx, slog.LevelError, "unmarshal from cache", slog.String("key", key), slog.Any("error", err))
 }
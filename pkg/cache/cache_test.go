@@ -0,0 +1,106 @@
+package cache_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/cache"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCoalesce(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	instance := cache.New("test", nil, strconv.Itoa, func(ctx context.Context, id int) (Repository, error) {
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 50)
+
+		return fetchRepository(ctx, id)
+	}, nil, nil)
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			got, err := instance.Get(context.Background(), 8000)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 8000, got.ID)
+		})
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestListCoalesce(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	instance := cache.New("test", nil, strconv.Itoa, noFetch, nil, nil).WithMissMany(func(ctx context.Context, ids []int) ([]Repository, error) {
+		calls.Add(int32(len(ids)))
+		time.Sleep(time.Millisecond * 50)
+
+		return fetchRepositories(ctx, ids)
+	})
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			got, err := instance.List(context.Background(), nil, 1, 2, 3)
+
+			assert.NoError(t, err)
+			assert.Len(t, got, 3)
+			assert.Equal(t, 2, got[1].ID)
+		})
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestGetFakeRedis(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := redis.NewFake()
+
+	var calls atomic.Int32
+
+	instance := cache.New("test", fake, strconv.Itoa, func(ctx context.Context, id int) (Repository, error) {
+		calls.Add(1)
+
+		return fetchRepository(ctx, id)
+	}, nil, nil).WithTTL(time.Minute)
+
+	readOnly := cache.New("test", fake, strconv.Itoa, noFetch, nil, nil)
+
+	_, err := instance.Get(ctx, 8000)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		got, err := readOnly.Get(ctx, 8000)
+
+		return err == nil && got.ID == 8000
+	}, time.Second, time.Millisecond*10)
+
+	fake.Advance(time.Minute)
+
+	_, err = readOnly.Get(ctx, 8000)
+	assert.Error(t, err)
+
+	_, err = instance.Get(ctx, 8000)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 )
 
-type bypassKey struct{}
+type (
+	bypassKey    struct{}
+	skipStoreKey struct{}
+)
 
 func Bypass(ctx context.Context) context.Context {
 	return context.WithValue(ctx, bypassKey{}, true)
@@ -20,3 +23,13 @@ func IsBypassed(ctx context.Context) bool {
 
 	return boolValue
 }
+
+func skipStore(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipStoreKey{}, true)
+}
+
+func isStoreSkipped(ctx context.Context) bool {
+	boolValue, _ := ctx.Value(skipStoreKey{}).(bool)
+
+	return boolValue
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// An envelope is a magic, the envelope format, flags, the schema version as big endian uint32, the write time in unix nanoseconds as big endian, then the serialized value.
+// Caches storing metadata write every value in an envelope, so content without one comes from another format and is a version mismatch: the payload is never guessed from its first bytes.
+const (
+	envelopeFormat     byte = 1
+	envelopeHeaderSize      = 18
+
+	// envelopeNotFound flags a cached not found result, without payload.
+	envelopeNotFound byte = 1 << 0
+)
+
+var envelopeMagic = [4]byte{0xff, 0x00, 'h', 'c'}
+
+var (
+	errVersionMismatch = errors.New("version mismatch")
+	errNewerVersion    = fmt.Errorf("newer %w", errVersionMismatch)
+	errNoEnvelope      = fmt.Errorf("no envelope: %w", errVersionMismatch)
+)
+
+type envelope struct {
+	writtenAt time.Time
+	payload   []byte
+	version   uint32
+	flags     byte
+}
+
+func (c *Cache[K, V]) withEnvelope() bool {
+	return c.softTTL != 0 || c.staleIfError != 0 || c.version != 0 || c.negativeTTL != 0
+}
+
+func wrapEnvelope(version uint32, writtenAt time.Time, payload []byte) []byte {
+	return newEnvelope(version, writtenAt, 0, payload)
+}
+
+func newEnvelope(version uint32, writtenAt time.Time, flags byte, payload []byte) []byte {
+	output := make([]byte, envelopeHeaderSize, envelopeHeaderSize+len(payload))
+
+	copy(output, envelopeMagic[:])
+	output[4] = envelopeFormat
+	output[5] = flags
+	binary.BigEndian.PutUint32(output[6:10], version)
+	binary.BigEndian.PutUint64(output[10:envelopeHeaderSize], uint64(writtenAt.UnixNano()))
+
+	return append(output, payload...)
+}
+
+// unwrapEnvelope returns false for content that is not an envelope of the current format.
+func unwrapEnvelope(content []byte) (envelope, bool) {
+	if len(content) < envelopeHeaderSize || [4]byte(content[:4]) != envelopeMagic || content[4] != envelopeFormat {
+		return envelope{}, false
+	}
+
+	return envelope{
+		flags:     content[5],
+		version:   binary.BigEndian.Uint32(content[6:10]),
+		writtenAt: time.Unix(0, int64(binary.BigEndian.Uint64(content[10:envelopeHeaderSize]))),
+		payload:   content[envelopeHeaderSize:],
+	}, true
+}
+
+func checkVersion(expected, actual uint32) error {
+	switch {
+	case actual > expected:
+		return errNewerVersion
+	case actual < expected:
+		return errVersionMismatch
+	default:
+		return nil
+	}
+}
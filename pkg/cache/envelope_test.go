@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		content   []byte
+		want      string
+		wantStore bool
+	}{
+		"same": {
+			content: wrapEnvelope(2, time.Now(), []byte("cached")),
+			want:    "cached",
+		},
+		"older": {
+			content:   wrapEnvelope(1, time.Now(), []byte("cached")),
+			want:      "fetched",
+			wantStore: true,
+		},
+		"legacy": {
+			content:   []byte("cached"),
+			want:      "fetched",
+			wantStore: true,
+		},
+		"newer": {
+			content: wrapEnvelope(3, time.Now(), []byte("cached")),
+			want:    "fetched",
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			stored := make(chan struct{})
+
+			mockRedisClient := mocks.NewRedisClient(ctrl)
+			mockRedisClient.EXPECT().Enabled().Return(true)
+			mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(testCase.content, nil)
+
+			if testCase.wantStore {
+				mockRedisClient.EXPECT().Store(gomock.Any(), "8000", gomock.Any(), time.Minute).DoAndReturn(func(_ context.Context, _ string, value any, _ time.Duration) error {
+					item, ok := unwrapEnvelope(value.([]byte))
+					assert.True(t, ok)
+					assert.Equal(t, uint32(2), item.version)
+					close(stored)
+
+					return nil
+				})
+			}
+
+			instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+				return "fetched", nil
+			}, nil, nil).
+				WithSerializer(StringSerializer{}).
+				WithTTL(time.Minute).
+				WithVersion(2)
+
+			got, err := instance.Get(context.Background(), 8000)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+
+			if testCase.wantStore {
+				select {
+				case <-stored:
+				case <-time.After(time.Second):
+					t.Error("value not stored")
+				}
+			} else {
+				time.Sleep(time.Millisecond * 50)
+			}
+		})
+	}
+}
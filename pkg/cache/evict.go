@@ -24,6 +24,8 @@ func (c *Cache[K, V]) EvictOnSuccess(ctx context.Context, id K, err error) error
 		return err
 	}
 
+	c.metrics.recordEviction(ctx)
+
 	return nil
 }
 
@@ -59,9 +59,9 @@ func TestEvictOnSuccess(t *testing.T) {
 				mockRedisClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.New("redis failed"))
 			}
 
-			instance := cache.New(mockRedisClient, strconv.Itoa, func(_ context.Context, id int) (string, error) {
+			instance := cache.New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, id int) (string, error) {
 				return "hello", nil
-			}, nil)
+			}, nil, nil)
 
 			gotErr := instance.EvictOnSuccess(context.Background(), 8000, testCase.args.err)
 			if testCase.wantErr == nil {
@@ -43,7 +43,7 @@ func TestGetSuite(t *testing.T) {
 
 func (gs *GetSuite) TestGet() {
 	gs.Run("no redis", func() {
-		instance := cache.New(nil, func(id int) string { return strconv.Itoa(id) }, noFetch, nil)
+		instance := cache.New("test", nil, func(id int) string { return strconv.Itoa(id) }, noFetch, nil, nil)
 
 		got, err := instance.Get(context.Background(), 1)
 		assert.ErrorContains(gs.T(), err, "not implemented")
@@ -51,7 +51,7 @@ func (gs *GetSuite) TestGet() {
 	})
 
 	gs.Run("bypassed", func() {
-		instance := cache.New(gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, noFetch, nil)
+		instance := cache.New("test", gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, noFetch, nil, nil)
 
 		got, err := instance.Get(cache.Bypass(context.Background()), 1)
 		assert.ErrorContains(gs.T(), err, "not implemented")
@@ -63,7 +63,7 @@ func (gs *GetSuite) TestGet() {
 		expected := getRepository(gs.T())
 		expected.ID = id
 
-		instance := cache.New(gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil)
+		instance := cache.New("test", gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil, nil)
 
 		got, err := instance.Get(context.Background(), id)
 		assert.NoError(gs.T(), err)
@@ -72,7 +72,7 @@ func (gs *GetSuite) TestGet() {
 		// Wait for async save
 		time.Sleep(time.Millisecond * 50)
 
-		instance = cache.New(gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, noFetch, nil)
+		instance = cache.New("test", gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, noFetch, nil, nil)
 
 		got, err = instance.Get(context.Background(), id)
 		assert.NoError(gs.T(), err)
@@ -87,7 +87,7 @@ func (gs *GetSuite) TestGet() {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		instance := cache.New(gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil).WithClientSideCaching(ctx, "fetch_and_store", 10)
+		instance := cache.New("test", gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil, nil).WithClientSideCaching(ctx, "fetch_and_store", 10)
 
 		got, err := instance.Get(context.Background(), id)
 		assert.NoError(gs.T(), err)
@@ -102,7 +102,7 @@ func (gs *GetSuite) TestGet() {
 	})
 
 	gs.Run("fetch not found", func() {
-		instance := cache.New(gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, noFetch, nil)
+		instance := cache.New("test", gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, noFetch, nil, nil)
 
 		got, err := instance.Get(context.Background(), 1)
 
@@ -117,7 +117,7 @@ func (gs *GetSuite) TestGet() {
 		err := gs.integration.Client().Store(context.Background(), strconv.Itoa(id), "{", 0)
 		assert.NoError(gs.T(), err)
 
-		instance := cache.New(gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil)
+		instance := cache.New("test", gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil, nil)
 
 		got, err := instance.Get(context.Background(), id)
 
@@ -137,7 +137,7 @@ func (gs *GetSuite) TestGet() {
 
 		expected, _ := fetchFuncStruct(context.Background(), 1)
 
-		instance := cache.New(gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchFuncStruct, nil)
+		instance := cache.New("test", gs.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchFuncStruct, nil, nil)
 
 		got, err := instance.Get(context.Background(), 1)
 
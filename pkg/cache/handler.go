@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+	"github.com/ViBiOh/httputils/v4/pkg/request"
+)
+
+// Handler lists the registered caches and inspects their keys. Evictions require a request signed with the secret, they are disabled without secret.
+//
+//	GET    /                    registered caches
+//	GET    /{name}/keys/{id}    presence and TTL of the id in memory and in Redis
+//	DELETE /{name}/keys/{id}    evicts the id
+//	DELETE /{name}/tags/{tag}   evicts the tag
+func (r *Registry) Handler(secret []byte) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, req *http.Request) {
+		httpjson.WriteArray(req.Context(), w, http.StatusOK, r.infos())
+	})
+
+	mux.HandleFunc("GET /{name}/keys/{id}", func(w http.ResponseWriter, req *http.Request) {
+		cache, ok := r.get(req.PathValue("name"))
+		if !ok {
+			httperror.NotFound(req.Context(), w, fmt.Errorf("unknown cache `%s`", req.PathValue("name")))
+
+			return
+		}
+
+		output, err := cache.inspect(req.Context(), req.PathValue("id"))
+		if httperror.HandleError(req.Context(), w, err) {
+			return
+		}
+
+		httpjson.Write(req.Context(), w, http.StatusOK, output)
+	})
+
+	mux.HandleFunc("DELETE /{name}/keys/{id}", r.evictHandler(secret, func(req *http.Request, cache inspectable) error {
+		return cache.evict(req.Context(), req.PathValue("id"))
+	}))
+
+	mux.HandleFunc("DELETE /{name}/tags/{tag}", r.evictHandler(secret, func(req *http.Request, cache inspectable) error {
+		return cache.evictTag(req.Context(), req.PathValue("tag"))
+	}))
+
+	return mux
+}
+
+func (r *Registry) evictHandler(secret []byte, action func(*http.Request, inspectable) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if len(secret) == 0 {
+			httperror.Forbidden(req.Context(), w)
+
+			return
+		}
+
+		if ok, err := request.ValidateSignature(req, secret); err != nil || !ok {
+			httperror.Unauthorized(req.Context(), w, err)
+
+			return
+		}
+
+		cache, ok := r.get(req.PathValue("name"))
+		if !ok {
+			httperror.NotFound(req.Context(), w, fmt.Errorf("unknown cache `%s`", req.PathValue("name")))
+
+			return
+		}
+
+		if httperror.HandleError(req.Context(), w, action(req, cache)) {
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
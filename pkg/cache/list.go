@@ -2,11 +2,15 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/ViBiOh/httputils/v4/pkg/concurrent"
 	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/model"
 	"github.com/ViBiOh/httputils/v4/pkg/telemetry"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -43,6 +47,8 @@ func (c *Cache[K, V]) List(ctx context.Context, onFetchErr FetchErrHandler[K], i
 	defer end(&err)
 
 	output, remainingIDs := c.memoryValues(ids)
+	c.metrics.recordMemoryHit(ctx, len(ids)-len(remainingIDs))
+
 	keys, values := c.redisValues(ctx, remainingIDs)
 
 	return c.handleList(ctx, onFetchErr, ids, output, remainingIDs, keys, values)
@@ -50,7 +56,7 @@ func (c *Cache[K, V]) List(ctx context.Context, onFetchErr FetchErrHandler[K], i
 
 func (c *Cache[K, V]) fetchAll(ctx context.Context, onFetchErr FetchErrHandler[K], ids []K) ([]V, error) {
 	if c.onMissMany != nil {
-		return c.onMissMany(ctx, ids)
+		return c.fetchMany(ctx, ids)
 	}
 
 	wg := concurrent.NewFailFast(c.concurrency)
@@ -61,15 +67,11 @@ func (c *Cache[K, V]) fetchAll(ctx context.Context, onFetchErr FetchErrHandler[K
 		wg.Go(func() error {
 			value, err := c.fetch(ctx, id)
 			if err != nil {
-				if onFetchErr != nil {
-					return onFetchErr(ctx, id, err)
-				}
-
-				slog.LogAttrs(ctx, slog.LevelError, "fetch id", slog.Any("id", id), slog.Any("error", err))
-			} else {
-				output[index] = value
+				return handleFetchErr(ctx, onFetchErr, id, err)
 			}
 
+			output[index] = value
+
 			return nil
 		})
 	}
@@ -77,10 +79,49 @@ func (c *Cache[K, V]) fetchAll(ctx context.Context, onFetchErr FetchErrHandler[K
 	return output, wg.Wait()
 }
 
+func handleFetchErr[K comparable](ctx context.Context, onFetchErr FetchErrHandler[K], id K, err error) error {
+	if onFetchErr != nil {
+		return onFetchErr(ctx, id, err)
+	}
+
+	slog.LogAttrs(ctx, slog.LevelError, "fetch id", slog.Any("id", id), slog.Any("error", err))
+
+	return nil
+}
+
+func (c *Cache[K, V]) fetchMany(ctx context.Context, ids []K) (output []V, err error) {
+	ctx, end := telemetry.StartSpan(ctx, c.tracer, "fetch_many", trace.WithSpanKind(trace.SpanKindInternal))
+	defer end(&err)
+
+	keys := make([]string, len(ids))
+	for index, id := range ids {
+		keys[index] = c.toKey(id)
+	}
+
+	output, coalesced, err := c.inflight.DoMany(ctx, keys, func(ctx context.Context, indexes []int) ([]V, error) {
+		missingIDs := make([]K, len(indexes))
+		for position, index := range indexes {
+			missingIDs[position] = ids[index]
+		}
+
+		defer c.metrics.recordFetch(ctx, time.Now())
+
+		return c.onMissMany(ctx, missingIDs)
+	})
+
+	c.recordCoalesced(ctx, coalesced)
+
+	return output, err
+}
+
 func (c *Cache[K, V]) handleList(ctx context.Context, onFetchErr FetchErrHandler[K], ids []K, output []V, remainings []K, keys, values []string) ([]V, error) {
 	var extendKeys []string
+	var staleIDs []K
 	var missingIDs IndexedIDs[K]
 
+	expiredValues := make(map[int]V)
+	newerIndexes := make(map[int]struct{})
+
 	remainingsLength, remainingsPos := len(remainings), 0
 
 	for index, id := range ids {
@@ -92,44 +133,98 @@ func (c *Cache[K, V]) handleList(ctx context.Context, onFetchErr FetchErrHandler
 			continue
 		}
 
-		if value, ok, err := c.decode([]byte(values[remainingsPos])); ok {
-			output[index] = value
+		key, content := keys[remainingsPos], []byte(values[remainingsPos])
+		remainingsPos++
 
-			if c.ttl != 0 && c.extender != nil {
-				extendKeys = append(extendKeys, keys[remainingsPos])
+		if c.isNotFound(content) {
+			if err := handleFetchErr(ctx, onFetchErr, id, ErrCachedNotFound); err != nil {
+				return output, err
 			}
 
-			c.memoryWrite(id, value, c.ttl)
-
-			remainingsPos++
-
 			continue
-		} else if err != nil {
-			logUnmarshalError(ctx, c.toKey(id), err)
 		}
 
-		remainingsPos++
+		value, writtenAt, ok, err := c.decode(content)
+		if err != nil {
+			logUnmarshalError(ctx, key, err)
+			c.recordDecodeError(ctx, err)
+
+			if errors.Is(err, errNewerVersion) {
+				newerIndexes[index] = struct{}{}
+			}
+		} else if ok {
+			ttl := c.entryTTL(value)
+
+			switch c.freshness(writtenAt, ttl) {
+			case fresh:
+				output[index] = value
+
+				if c.ttl != 0 && c.extender != nil {
+					extendKeys = append(extendKeys, key)
+				}
+
+				c.memoryWrite(id, value, c.freshTTL(writtenAt, ttl))
+
+				continue
+
+			case stale:
+				output[index] = value
+				staleIDs = append(staleIDs, id)
+
+				continue
+
+			case expired:
+				if c.servableOnError(writtenAt, ttl) {
+					expiredValues[index] = value
+				}
+			}
+		}
 
 		missingIDs = append(missingIDs, IndexedID[K]{id: id, index: index})
 	}
 
+	c.metrics.recordRedisHit(ctx, len(remainings)-len(missingIDs))
+	c.metrics.recordMiss(ctx, len(missingIDs))
+
 	c.extendTTL(ctx, extendKeys...)
+	c.refreshMany(ctx, staleIDs)
 
 	if len(missingIDs) == 0 {
 		return output, nil
 	}
 
-	missingValues, err := c.fetchAll(ctx, onFetchErr, missingIDs.IDs())
+	fetchCtx := ctx
+	if len(newerIndexes) != 0 {
+		fetchCtx = skipStore(ctx)
+	}
+
+	missingValues, err := c.fetchAll(fetchCtx, onFetchErr, missingIDs.IDs())
 	if err != nil {
-		return output, fmt.Errorf("fetch many: %w", err)
+		if len(expiredValues) != len(missingIDs) || errors.Is(err, model.ErrNotFound) {
+			return output, fmt.Errorf("fetch many: %w", err)
+		}
+
+		slog.LogAttrs(ctx, slog.LevelWarn, "serving stale values", slog.Int("count", len(expiredValues)), slog.Any("error", err))
+
+		for index, value := range expiredValues {
+			output[index] = value
+		}
+
+		return output, nil
 	}
 
 	for index, missing := range missingIDs {
 		output[missing.index] = missingValues[index]
 	}
 
-	go doInBackground(context.WithoutCancel(ctx), func(ctx context.Context) error {
-		return c.storeMany(ctx, output, missingIDs)
+	toStore := slices.DeleteFunc(missingIDs, func(missing IndexedID[K]) bool {
+		_, ok := newerIndexes[missing.index]
+
+		return ok
+	})
+
+	c.background(ctx, "", func(ctx context.Context) error {
+		return c.storeMany(ctx, output, toStore)
 	})
 
 	return output, nil
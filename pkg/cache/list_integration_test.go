@@ -43,7 +43,7 @@ func TestListSuite(t *testing.T) {
 
 func (s *ListSuite) TestList() {
 	s.Run("no item", func() {
-		instance := cache.New(nil, func(id int) string { return strconv.Itoa(id) }, noFetch, nil)
+		instance := cache.New("test", nil, func(id int) string { return strconv.Itoa(id) }, noFetch, nil, nil)
 
 		got, err := instance.List(context.Background(), nil)
 		assert.Nil(s.T(), err)
@@ -51,7 +51,7 @@ func (s *ListSuite) TestList() {
 	})
 
 	s.Run("bypassed", func() {
-		instance := cache.New(nil, func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil)
+		instance := cache.New("test", nil, func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil, nil)
 
 		first := getRepository(s.T())
 		first.ID = 10
@@ -66,7 +66,7 @@ func (s *ListSuite) TestList() {
 	})
 
 	s.Run("no memory no redis", func() {
-		instance := cache.New(nil, func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil)
+		instance := cache.New("test", nil, func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil, nil)
 
 		first := getRepository(s.T())
 		first.ID = 10
@@ -81,7 +81,7 @@ func (s *ListSuite) TestList() {
 	})
 
 	s.Run("no memory no redis, load many", func() {
-		instance := cache.New(nil, func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil).
+		instance := cache.New("test", nil, func(id int) string { return strconv.Itoa(id) }, fetchRepository, nil, nil).
 			WithMissMany(fetchRepositories)
 
 		first := getRepository(s.T())
@@ -100,7 +100,7 @@ func (s *ListSuite) TestList() {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		instance := cache.New(nil, func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil).
+		instance := cache.New("test", nil, func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil, nil).
 			WithClientSideCaching(ctx, "memory_no_redis", 10)
 
 		first := getRepository(s.T())
@@ -127,7 +127,7 @@ func (s *ListSuite) TestList() {
 	})
 
 	s.Run("no memory and redis", func() {
-		instance := cache.New(s.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil)
+		instance := cache.New("test", s.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil, nil)
 
 		first := getRepository(s.T())
 		first.ID = 10
@@ -156,7 +156,7 @@ func (s *ListSuite) TestList() {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		instance := cache.New(s.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil).
+		instance := cache.New("test", s.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil, nil).
 			WithClientSideCaching(ctx, "memory_redis", 10)
 
 		first := getRepository(s.T())
@@ -206,7 +206,7 @@ func (s *ListSuite) TestList() {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		instance := cache.New(s.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil).
+		instance := cache.New("test", s.integration.Client(), func(id int) string { return strconv.Itoa(id) }, fetchOnce(), nil, nil).
 			WithClientSideCaching(ctx, "memory_redis_may_extend", 10).
 			WithTTL(time.Hour).
 			WithExtendOnHit(ctx, time.Hour/4, 10).
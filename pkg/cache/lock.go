@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var lockPollInterval = time.Millisecond * 25
+
+func lockKey(key string) string {
+	return key + ":lock"
+}
+
+// fetchExclusive calls onMiss while holding a distributed lock on the key, or waits for the lock holder to store the value. The boolean is false when the caller has to fetch by itself.
+func (c *Cache[K, V]) fetchExclusive(ctx context.Context, id K) (value V, ok bool, err error) {
+	if c.lockTTL == 0 || c.write == nil || IsBypassed(ctx) || isStoreSkipped(ctx) {
+		return value, false, nil
+	}
+
+	key := c.toKey(id)
+
+	acquired, lockErr := c.write.Exclusive(ctx, lockKey(key), c.lockTTL, func(ctx context.Context) error {
+		ok = true
+
+		if value, err = c.callOnMiss(ctx, id); err != nil {
+			if c.shouldStoreNotFound(err) {
+				if storeErr := c.storeNotFound(ctx, id); storeErr != nil {
+					slog.LogAttrs(ctx, slog.LevelError, "store not found under lock", slog.String("key", key), slog.Any("error", storeErr))
+				}
+			}
+
+			return nil
+		}
+
+		if storeErr := c.store(ctx, id, value); storeErr != nil {
+			slog.LogAttrs(ctx, slog.LevelError, "store under lock", slog.String("key", key), slog.Any("error", storeErr))
+		}
+
+		return nil
+	})
+	if lockErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "distributed lock", slog.String("key", key), slog.Any("error", lockErr))
+	}
+
+	if acquired || lockErr != nil {
+		return value, ok, err
+	}
+
+	return c.waitForValue(ctx, id, key)
+}
+
+// waitForValue polls Redis for the value stored by the lock holder. Redis keeps values during the stale-if-error window, so a value that is not fresh is the previous one and polling continues.
+func (c *Cache[K, V]) waitForValue(ctx context.Context, id K, key string) (value V, ok bool, err error) {
+	if c.read == nil {
+		return value, false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.lockWait)
+	defer cancel()
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return value, false, nil
+		case <-ticker.C:
+		}
+
+		content, err := c.read.Load(ctx, key)
+		if err != nil {
+			continue
+		}
+
+		if c.isNotFound(content) {
+			return value, true, ErrCachedNotFound
+		}
+
+		var writtenAt time.Time
+
+		if value, writtenAt, ok, err = c.decode(content); errors.Is(err, errVersionMismatch) {
+			continue
+		} else if err != nil {
+			logUnmarshalError(ctx, key, err)
+
+			return value, false, nil
+		} else if ok && c.freshness(writtenAt, c.entryTTL(value)) == fresh {
+			c.memoryWrite(id, value, c.freshTTL(writtenAt, c.entryTTL(value)))
+
+			return value, true, nil
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/cache"
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWithDistributedLock(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		onMiss  func(context.Context, int) (string, error)
+		want    string
+		wantErr error
+	}{
+		"acquired": {
+			func(_ context.Context, _ int) (string, error) {
+				return "fetched", nil
+			},
+			"fetched",
+			nil,
+		},
+		"acquired error": {
+			func(_ context.Context, _ int) (string, error) {
+				return "", errors.New("fetch failed")
+			},
+			"",
+			errors.New("fetch failed"),
+		},
+		"wait": {
+			func(_ context.Context, _ int) (string, error) {
+				return "", errors.New("should not be called")
+			},
+			"stored",
+			nil,
+		},
+		"wait timeout": {
+			func(_ context.Context, _ int) (string, error) {
+				return "fetched", nil
+			},
+			"fetched",
+			nil,
+		},
+		"lock error": {
+			func(_ context.Context, _ int) (string, error) {
+				return "fetched", nil
+			},
+			"fetched",
+			nil,
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRedisClient := mocks.NewRedisClient(ctrl)
+			mockRedisClient.EXPECT().Enabled().Return(true)
+			mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(nil, nil)
+			mockRedisClient.EXPECT().Store(gomock.Any(), "8000", gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+			switch intention {
+			case "acquired", "acquired error":
+				mockRedisClient.EXPECT().Exclusive(gomock.Any(), "8000:lock", time.Second, gomock.Any()).DoAndReturn(func(ctx context.Context, _ string, _ time.Duration, action func(context.Context) error) (bool, error) {
+					return true, action(ctx)
+				})
+			case "wait":
+				mockRedisClient.EXPECT().Exclusive(gomock.Any(), "8000:lock", time.Second, gomock.Any()).Return(false, nil)
+				mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(nil, nil)
+				mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return([]byte("stored"), nil)
+			case "wait timeout":
+				mockRedisClient.EXPECT().Exclusive(gomock.Any(), "8000:lock", time.Second, gomock.Any()).Return(false, nil)
+				mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(nil, nil).AnyTimes()
+			case "lock error":
+				mockRedisClient.EXPECT().Exclusive(gomock.Any(), "8000:lock", time.Second, gomock.Any()).Return(false, errors.New("redis failed"))
+			}
+
+			instance := cache.New("test", mockRedisClient, strconv.Itoa, testCase.onMiss, nil, nil).
+				WithSerializer(cache.StringSerializer{}).
+				WithDistributedLock(time.Second, time.Millisecond*100)
+
+			got, gotErr := instance.Get(context.Background(), 8000)
+
+			assert.Equal(t, testCase.want, got)
+
+			if testCase.wantErr == nil {
+				assert.NoError(t, gotErr)
+			} else {
+				assert.ErrorContains(t, gotErr, testCase.wantErr.Error())
+			}
+		})
+	}
+}
@@ -26,13 +26,44 @@ func (c *Cache[K, V]) memoryWrite(id K, value V, ttl time.Duration) {
 	c.memory.Set(id, value, ttl)
 }
 
-func (c *Cache[K, V]) subscribe(ctx context.Context) {
+// subscribe listens to the evictions of the other instances, the tag evictions only for a tagged cache.
+func (c *Cache[K, V]) subscribe(ctx context.Context, tagged bool) {
 	if c.read == nil {
 		return
 	}
 
+	if tagged {
+		go redis.SubscribeFor(ctx, c.read, tagChannel(c.channel), func(tag string, err error) {
+			if err != nil {
+				slog.LogAttrs(ctx, slog.LevelError, "decode tag eviction", slog.String("channel", tagChannel(c.channel)), slog.Any("error", err))
+
+				return
+			}
+
+			slog.LogAttrs(ctx, slog.LevelDebug, "evicting tag from memory cache", slog.String("tag", tag), slog.String("channel", c.channel))
+			c.memoryDeleteTag(tag)
+		})
+	}
+
+	// Both subscriptions lose their connection when the server goes away, a single flush covers them.
+
 	redis.SubscribeFor(ctx, c.read, c.channel, func(id K, err error) {
+		if err != nil {
+			slog.LogAttrs(ctx, slog.LevelError, "decode eviction", slog.String("channel", c.channel), slog.Any("error", err))
+
+			return
+		}
+
 		slog.LogAttrs(ctx, slog.LevelDebug, "evicting from memory cache", slog.Any("id", id), slog.String("channel", c.channel))
 		c.memory.Delete(id)
+	}, redis.OnReconnect(c.memoryFlush))
+}
+
+// memoryFlush empties the memory cache, evictions may have been missed while the subscription was interrupted.
+func (c *Cache[K, V]) memoryFlush(ctx context.Context) {
+	slog.LogAttrs(ctx, slog.LevelWarn, "flushing memory cache after reconnection", slog.String("channel", c.channel))
+
+	c.memory.DeleteFunc(func(K, V) bool {
+		return true
 	})
 }
@@ -49,9 +49,7 @@ func (c *Cache[K, V]) startEvicter(done <-chan struct{}) {
 				continue
 			}
 
-			c.mutex.Lock()
-			delete(c.content, toExpire.id)
-			c.mutex.Unlock()
+			c.shard(toExpire.id).delete(toExpire.id)
 
 		case update, ok := <-c.expirationUpdates:
 			if !ok {
@@ -98,3 +96,10 @@ func (c *Cache[K, V]) addExpiration(id K, ttl time.Duration) {
 	case c.expirationUpdates <- ExpirationQueueAction[K]{id: id, ttl: ttl, action: AddItem}:
 	}
 }
+
+func (c *Cache[K, V]) removeExpiration(id K) {
+	select {
+	case <-c.done:
+	case c.expirationUpdates <- ExpirationQueueAction[K]{id: id, action: RemoveItem}:
+	}
+}
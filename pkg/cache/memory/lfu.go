@@ -0,0 +1,108 @@
+package memory
+
+import "container/list"
+
+type frequencyEntry[K comparable] struct {
+	id        K
+	cost      int
+	frequency int
+}
+
+// LFU evicts the least frequently used entries, the least recently used first among entries of the same frequency.
+type LFU[K comparable] struct {
+	index        map[K]*list.Element
+	frequencies  map[int]*list.List
+	capacity     int
+	used         int
+	minFrequency int
+}
+
+func NewLFU[K comparable](capacity int) Policy[K] {
+	return &LFU[K]{
+		index:       make(map[K]*list.Element),
+		frequencies: make(map[int]*list.List),
+		capacity:    capacity,
+	}
+}
+
+func (l *LFU[K]) Add(id K, cost int) []K {
+	l.Remove(id)
+
+	if cost > l.capacity {
+		return []K{id}
+	}
+
+	var evicted []K
+
+	for l.used+cost > l.capacity {
+		evicted = append(evicted, l.evict())
+	}
+
+	l.push(&frequencyEntry[K]{id: id, cost: cost, frequency: 1})
+	l.minFrequency = 1
+
+	return evicted
+}
+
+func (l *LFU[K]) Touch(id K) {
+	element, ok := l.index[id]
+	if !ok {
+		return
+	}
+
+	item := l.remove(element)
+	item.frequency++
+
+	l.push(item)
+
+	if _, ok := l.frequencies[l.minFrequency]; !ok && l.minFrequency == item.frequency-1 {
+		l.minFrequency = item.frequency
+	}
+}
+
+func (l *LFU[K]) Remove(id K) {
+	if element, ok := l.index[id]; ok {
+		l.remove(element)
+	}
+}
+
+func (l *LFU[K]) evict() K {
+	if _, ok := l.frequencies[l.minFrequency]; !ok {
+		l.minFrequency = 0
+
+		for frequency := range l.frequencies {
+			if l.minFrequency == 0 || frequency < l.minFrequency {
+				l.minFrequency = frequency
+			}
+		}
+	}
+
+	return l.remove(l.frequencies[l.minFrequency].Back()).id
+}
+
+func (l *LFU[K]) push(item *frequencyEntry[K]) {
+	entries, ok := l.frequencies[item.frequency]
+	if !ok {
+		entries = list.New()
+		l.frequencies[item.frequency] = entries
+	}
+
+	l.index[item.id] = entries.PushFront(item)
+	l.used += item.cost
+}
+
+func (l *LFU[K]) remove(element *list.Element) *frequencyEntry[K] {
+	item := element.Value.(*frequencyEntry[K])
+
+	entries := l.frequencies[item.frequency]
+	entries.Remove(element)
+
+	if entries.Len() == 0 {
+		delete(l.frequencies, item.frequency)
+	}
+
+	delete(l.index, item.id)
+	l.used -= item.cost
+
+	return item
+}
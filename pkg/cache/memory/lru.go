@@ -1,91 +1,97 @@
 package memory
 
-import (
-	"context"
+import "container/list"
 
-	"github.com/ViBiOh/httputils/v4/pkg/concurrent"
-)
+type entry[K comparable] struct {
+	id   K
+	cost int
+}
 
-type LRUAction int
+// LRU evicts the least recently used entries.
+type LRU[K comparable] struct {
+	list     *list.List
+	index    map[K]*list.Element
+	capacity int
+	used     int
+}
 
-const (
-	Touch LRUAction = iota
-	Add   LRUAction = iota
-)
+func NewLRU[K comparable](capacity int) Policy[K] {
+	return newLRU[K](capacity)
+}
 
-type LeastRecentlyUsedAction[K comparable] struct {
-	id     K
-	action LRUAction
+func newLRU[K comparable](capacity int) *LRU[K] {
+	return &LRU[K]{
+		list:     list.New(),
+		index:    make(map[K]*list.Element),
+		capacity: capacity,
+	}
 }
 
-func (c *Cache[K, V]) startLRU(ctx context.Context) {
-	defer close(c.lruUpdates)
+func (l *LRU[K]) Add(id K, cost int) []K {
+	rejected := l.add(id, cost)
 
-	if c.maxSize == 0 {
-		return
+	evicted := make([]K, len(rejected))
+	for index, item := range rejected {
+		evicted[index] = item.id
 	}
 
-	concurrent.ChanUntilDone(ctx, c.lruUpdates, func(update LeastRecentlyUsedAction[K]) {
-		switch update.action {
-		case Touch:
-			c.refreshEntryLRU(update.id)
-		case Add:
-			c.addEntryLRU(update.id)
-		}
-	}, c.close)
+	return evicted
 }
 
-func (c *Cache[K, V]) touchLRU(id K) {
-	if c.maxSize == 0 {
-		return
+func (l *LRU[K]) Touch(id K) {
+	if element, ok := l.index[id]; ok {
+		l.list.MoveToFront(element)
 	}
+}
 
-	c.sendLRUAction(LeastRecentlyUsedAction[K]{id: id, action: Touch})
+func (l *LRU[K]) Remove(id K) {
+	if element, ok := l.index[id]; ok {
+		l.remove(element)
+	}
 }
 
-func (c *Cache[K, V]) addLRU(id K) {
-	if c.maxSize == 0 {
-		return
+func (l *LRU[K]) add(id K, cost int) []entry[K] {
+	l.Remove(id)
+
+	if cost > l.capacity {
+		return []entry[K]{{id: id, cost: cost}}
 	}
 
-	c.sendLRUAction(LeastRecentlyUsedAction[K]{id: id, action: Add})
-}
+	var evicted []entry[K]
 
-func (c *Cache[K, V]) refreshEntryLRU(id K) {
-	if element, ok := c.lruIndex[id]; ok {
-		c.lru.MoveToFront(element)
+	for l.used+cost > l.capacity {
+		evicted = append(evicted, l.remove(l.list.Back()))
 	}
+
+	l.push(entry[K]{id: id, cost: cost})
+
+	return evicted
+}
+
+func (l *LRU[K]) push(item entry[K]) {
+	l.index[item.id] = l.list.PushFront(item)
+	l.used += item.cost
 }
 
-func (c *Cache[K, V]) addEntryLRU(id K) {
-	if element, ok := c.lruIndex[id]; ok {
-		c.lru.MoveToFront(element)
-		return
+func (l *LRU[K]) back() (entry[K], bool) {
+	if element := l.list.Back(); element != nil {
+		return element.Value.(entry[K]), true
 	}
 
-	if c.lru.Len() == c.maxSize {
-		back := c.lru.Back()
-		c.lru.Remove(back)
+	return entry[K]{}, false
+}
 
-		evictedID := back.Value.(K)
-		delete(c.lruIndex, evictedID)
+func (l *LRU[K]) remove(element *list.Element) entry[K] {
+	item := l.list.Remove(element).(entry[K])
 
-		c.Delete(evictedID)
-	}
+	delete(l.index, item.id)
+	l.used -= item.cost
 
-	c.lruIndex[id] = c.lru.PushFront(id)
+	return item
 }
 
-func (c *Cache[K, V]) sendLRUAction(action LeastRecentlyUsedAction[K]) {
-	select {
-	case <-c.done:
-		return
-	default:
-	}
+func (l *LRU[K]) contains(id K) bool {
+	_, ok := l.index[id]
 
-	select {
-	case <-c.done:
-	case c.lruUpdates <- action:
-	default:
-	}
+	return ok
 }
@@ -1,41 +1,78 @@
 package memory
 
 import (
-	"container/list"
 	"context"
+	"hash/maphash"
 	"runtime"
 	"sync"
 	"time"
 )
 
+const (
+	maxShards        = 64
+	minShardCapacity = 64
+)
+
 type Cache[K comparable, V any] struct {
 	done              chan struct{}
-	content           map[K]V
 	expiration        *ExpirationQueue[K]
-	lru               *list.List
-	lruIndex          map[K]*list.Element
+	cost              func(K, V) int
 	expirationUpdates chan ExpirationQueueAction[K]
-	lruUpdates        chan LeastRecentlyUsedAction[K]
-	mutex             sync.RWMutex
-	maxSize           int
+	shards            []*shard[K, V]
+	seed              maphash.Seed
+	mask              uint64
+}
+
+// New creates a cache holding up to capacity entries, split in shards that evict with their own policy. A zero capacity or a nil policy never evicts.
+func New[K comparable, V any](capacity int, policy PolicyFactory[K]) *Cache[K, V] {
+	shards := maxShards
+	if capacity != 0 && policy != nil {
+		shards = shardCount(capacity)
+	}
+
+	return newCache[K, V](capacity, policy, shards)
+}
+
+// shardCount is the largest power of two keeping at least minShardCapacity per shard, so small caches stay exact.
+func shardCount(capacity int) int {
+	shards := 1
+	for shards < maxShards && capacity/(shards*2) >= minShardCapacity {
+		shards *= 2
+	}
+
+	return shards
 }
 
-func New[K comparable, V any](maxSize int) *Cache[K, V] {
-	return &Cache[K, V]{
+func newCache[K comparable, V any](capacity int, policy PolicyFactory[K], shards int) *Cache[K, V] {
+	cache := &Cache[K, V]{
 		done:              make(chan struct{}),
-		content:           make(map[K]V),
 		expiration:        NewExpirationQueue[K](),
 		expirationUpdates: make(chan ExpirationQueueAction[K], runtime.NumCPU()),
-		lruUpdates:        make(chan LeastRecentlyUsedAction[K], runtime.NumCPU()*10), // read ratio 10:1
-		lru:               list.New(),
-		lruIndex:          make(map[K]*list.Element),
-		maxSize:           maxSize,
+		shards:            make([]*shard[K, V], shards),
+		seed:              maphash.MakeSeed(),
+		mask:              uint64(shards - 1),
 	}
+
+	for index := range cache.shards {
+		var shardPolicy Policy[K]
+		if capacity != 0 && policy != nil {
+			shardPolicy = policy((capacity + shards - 1) / shards)
+		}
+
+		cache.shards[index] = newShard[K, V](shardPolicy)
+	}
+
+	return cache
 }
 
-func (c *Cache[K, V]) Start(ctx context.Context) {
-	go c.startLRU(ctx)
+// WithCost bounds the cache by the sum of the entries' cost instead of their count, e.g. an approximate size in bytes. It has to be called before using the cache.
+func (c *Cache[K, V]) WithCost(cost func(K, V) int) *Cache[K, V] {
+	c.cost = cost
+
+	return c
+}
 
+func (c *Cache[K, V]) Start(ctx context.Context) {
 	c.startEvicter(ctx.Done())
 }
 
@@ -45,54 +82,67 @@ func (c *Cache[K, V]) close() {
 	})
 }
 
-func (c *Cache[K, V]) Get(id K) (V, bool) {
-	c.mutex.RLock()
-
-	output, ok := c.content[id]
-	if ok {
-		c.touchLRU(id)
-	}
-
-	c.mutex.RUnlock()
+func (c *Cache[K, V]) shard(id K) *shard[K, V] {
+	return c.shards[maphash.Comparable(c.seed, id)&c.mask]
+}
 
-	return output, ok
+func (c *Cache[K, V]) Get(id K) (V, bool) {
+	return c.shard(id).get(id)
 }
 
 func (c *Cache[K, V]) GetAll(ids []K, output []V) []K {
 	var missingIDs []K
 
-	c.mutex.RLock()
-
 	for index, id := range ids {
-		if value, ok := c.content[id]; ok {
+		if value, ok := c.shard(id).get(id); ok {
 			output[index] = value
-			c.touchLRU(id)
 		} else {
 			missingIDs = append(missingIDs, id)
 		}
 	}
 
-	c.mutex.RUnlock()
-
 	return missingIDs
 }
 
 func (c *Cache[K, V]) Set(id K, value V, ttl time.Duration) {
-	c.mutex.Lock()
+	cost := 1
+	if c.cost != nil {
+		cost = c.cost(id, value)
+	}
 
-	c.content[id] = value
+	evicted := c.shard(id).set(id, value, cost, ttl)
 
 	c.addExpiration(id, ttl)
-	c.addLRU(id)
 
-	c.mutex.Unlock()
+	for _, evictedID := range evicted {
+		c.removeExpiration(evictedID)
+	}
 }
 
 func (c *Cache[K, V]) Delete(id K) {
-	c.mutex.Lock()
+	c.shard(id).delete(id)
+	c.removeExpiration(id)
+}
+
+func (c *Cache[K, V]) DeleteFunc(predicate func(K, V) bool) {
+	for _, shard := range c.shards {
+		for _, id := range shard.matching(predicate) {
+			c.Delete(id)
+		}
+	}
+}
 
-	delete(c.content, id)
-	c.expirationUpdates <- ExpirationQueueAction[K]{id: id, action: RemoveItem}
+func (c *Cache[K, V]) Len() int {
+	var output int
+
+	for _, shard := range c.shards {
+		output += shard.len()
+	}
+
+	return output
+}
 
-	c.mutex.Unlock()
+// Expiration returns the expiration time of the entry without touching the eviction policy, zero when it doesn't expire. The boolean is false when the entry is absent.
+func (c *Cache[K, V]) Expiration(id K) (time.Time, bool) {
+	return c.shard(id).expiration(id)
 }
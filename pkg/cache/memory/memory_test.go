@@ -16,7 +16,7 @@ func TestGet(t *testing.T) {
 
 		ctx := t.Context()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 		go instance.Start(ctx)
 
 		instance.Set("hello", "world", time.Second)
@@ -34,7 +34,7 @@ func TestGet(t *testing.T) {
 
 		ctx := t.Context()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 		go instance.Start(ctx)
 
 		instance.Set("hello", "world", time.Millisecond*50)
@@ -54,7 +54,7 @@ func TestGet(t *testing.T) {
 
 		ctx := t.Context()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 		go instance.Start(ctx)
 
 		got, found := instance.Get("hello")
@@ -72,7 +72,7 @@ func TestGetAll(t *testing.T) {
 	t.Run("nothing found", func(t *testing.T) {
 		t.Parallel()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 
 		output := make([]string, 5)
 
@@ -88,7 +88,7 @@ func TestGetAll(t *testing.T) {
 	t.Run("part found", func(t *testing.T) {
 		t.Parallel()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 
 		instance.Set("2", "two", 0)
 		instance.Set("5", "five", 0)
@@ -107,7 +107,7 @@ func TestGetAll(t *testing.T) {
 	t.Run("all found", func(t *testing.T) {
 		t.Parallel()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 
 		instance.Set("1", "one", 0)
 		instance.Set("2", "two", 0)
@@ -131,7 +131,7 @@ func TestGetAll(t *testing.T) {
 
 		ctx := t.Context()
 
-		instance := New[string, string](3)
+		instance := New[string, string](3, NewLRU[string])
 		go instance.Start(ctx)
 
 		instance.Set("1", "one", 0)
@@ -165,18 +165,17 @@ func BenchmarkLRURefresh(b *testing.B) {
 func benchmarkLRURefresh(b *testing.B, size int) {
 	b.Helper()
 
-	instance := New[int, int](size)
+	instance := newLRU[int](size)
 
 	for i := range size {
-		instance.content[i] = i
-		instance.lruIndex[i] = instance.lru.PushBack(i)
+		instance.Add(i, 1)
 	}
 
 	// Cycle through all elements so the scan position varies
 	var i int
 
 	for b.Loop() {
-		instance.refreshEntryLRU(i % size)
+		instance.Touch(i % size)
 		i++
 	}
 }
@@ -189,7 +188,7 @@ func TestDelete(t *testing.T) {
 
 		ctx := t.Context()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 		go instance.Start(ctx)
 
 		instance.Delete("hello")
@@ -207,7 +206,7 @@ func TestDelete(t *testing.T) {
 
 		ctx := t.Context()
 
-		instance := New[string, string](0)
+		instance := New[string, string](0, nil)
 		go instance.Start(ctx)
 
 		instance.Set("hello", "world", time.Second)
@@ -223,3 +222,27 @@ func TestDelete(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+func TestDeleteFunc(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	instance := New[string, string](0, nil)
+	go instance.Start(ctx)
+
+	instance.Set("1", "odd", time.Second)
+	instance.Set("2", "even", time.Second)
+	instance.Set("3", "odd", time.Second)
+
+	instance.DeleteFunc(func(_ string, value string) bool {
+		return value == "odd"
+	})
+
+	output := make([]string, 3)
+
+	got := instance.GetAll([]string{"1", "2", "3"}, output)
+
+	assert.Equal(t, []string{"", "even", ""}, output)
+	assert.Equal(t, []string{"1", "3"}, got)
+}
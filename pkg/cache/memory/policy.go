@@ -0,0 +1,12 @@
+package memory
+
+// Policy decides which entries are evicted when the cache is over its capacity. The cache serializes the calls, implementations don't have to be safe for concurrent use.
+type Policy[K comparable] interface {
+	// Add records an entry of the given cost and returns the ids to evict. They contain the added id when it is not admitted.
+	Add(id K, cost int) []K
+	Touch(id K)
+	Remove(id K)
+}
+
+// PolicyFactory creates the policy of a shard for its share of the capacity, e.g. NewLRU.
+type PolicyFactory[K comparable] func(capacity int) Policy[K]
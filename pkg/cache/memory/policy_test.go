@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	t.Parallel()
+
+	instance := NewLRU[string](3)
+
+	assert.Empty(t, instance.Add("1", 1))
+	assert.Empty(t, instance.Add("2", 1))
+	assert.Empty(t, instance.Add("3", 1))
+
+	instance.Touch("1")
+
+	assert.Equal(t, []string{"2"}, instance.Add("4", 1))
+	assert.Equal(t, []string{"3", "1"}, instance.Add("5", 2))
+	assert.Equal(t, []string{"6"}, instance.Add("6", 4))
+
+	instance.Remove("5")
+
+	assert.Empty(t, instance.Add("7", 2))
+}
+
+func TestLFU(t *testing.T) {
+	t.Parallel()
+
+	instance := NewLFU[string](3)
+
+	assert.Empty(t, instance.Add("1", 1))
+	assert.Empty(t, instance.Add("2", 1))
+	assert.Empty(t, instance.Add("3", 1))
+
+	instance.Touch("1")
+	instance.Touch("1")
+	instance.Touch("2")
+
+	assert.Equal(t, []string{"3"}, instance.Add("4", 1))
+	assert.Equal(t, []string{"4"}, instance.Add("5", 1))
+
+	instance.Remove("5")
+	instance.Touch("2")
+	instance.Touch("2")
+
+	assert.Equal(t, []string{"1"}, instance.Add("6", 2))
+	assert.Equal(t, []string{"7"}, instance.Add("7", 4))
+}
+
+func TestTinyLFU(t *testing.T) {
+	t.Parallel()
+
+	instance := NewTinyLFU[int](100)
+
+	for id := range 100 {
+		assert.Empty(t, instance.Add(id, 1))
+
+		for range 3 {
+			instance.Touch(id)
+		}
+	}
+
+	var evicted []int
+
+	for id := 100; id < 200; id++ {
+		evicted = append(evicted, instance.Add(id, 1)...)
+	}
+
+	assert.Len(t, evicted, 100)
+
+	var rejected int
+
+	for _, id := range evicted {
+		if id >= 100 {
+			rejected++
+		}
+	}
+
+	assert.GreaterOrEqual(t, rejected, 90, "one-hit entries should not evict frequent ones")
+}
+
+func TestCost(t *testing.T) {
+	t.Parallel()
+
+	instance := New[string, string](10, NewLRU[string]).WithCost(func(_ string, value string) int {
+		return len(value)
+	})
+
+	instance.Set("1", "hello", 0)
+	instance.Set("2", "world", 0)
+
+	go instance.Start(t.Context())
+
+	instance.Set("3", "!", 0)
+
+	output := make([]string, 3)
+
+	assert.Equal(t, []string{"1"}, instance.GetAll([]string{"1", "2", "3"}, output))
+	assert.Equal(t, []string{"", "world", "!"}, output)
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// touchBufferSize is the number of hits a shard records between two replays, the next ones are dropped until a writer replays them.
+const touchBufferSize = 64
+
+// touchBuffer records the hits of a shard without locking. Readers claim a slot while holding the read lock, it's replayed while holding the write lock, so they never overlap.
+type touchBuffer[K comparable] struct {
+	ids  [touchBufferSize]K
+	next atomic.Uint64
+}
+
+// push records the hit, it reports false when the buffer is full. The read lock has to be held.
+func (t *touchBuffer[K]) push(id K) bool {
+	index := t.next.Add(1) - 1
+	if index >= touchBufferSize {
+		return false
+	}
+
+	t.ids[index] = id
+
+	return true
+}
+
+// replay touches the recorded hits in the policy, then empties the buffer. The write lock has to be held.
+func (t *touchBuffer[K]) replay(policy Policy[K]) {
+	count := min(t.next.Load(), touchBufferSize)
+
+	var zero K
+
+	for index := range count {
+		policy.Touch(t.ids[index])
+		t.ids[index] = zero
+	}
+
+	t.next.Store(0)
+}
+
+type shard[K comparable, V any] struct {
+	content     map[K]V
+	expirations map[K]time.Time
+	policy      Policy[K]
+	touches     touchBuffer[K]
+	mutex       sync.RWMutex
+}
+
+func newShard[K comparable, V any](policy Policy[K]) *shard[K, V] {
+	return &shard[K, V]{
+		content:     make(map[K]V),
+		expirations: make(map[K]time.Time),
+		policy:      policy,
+	}
+}
+
+// get records the hit in the touch buffer instead of updating the policy, so readers never wait for each other. A reader filling the buffer replays it only when no one holds the shard.
+func (s *shard[K, V]) get(id K) (V, bool) {
+	s.mutex.RLock()
+	output, ok := s.content[id]
+
+	full := ok && s.policy != nil && !s.touches.push(id)
+	s.mutex.RUnlock()
+
+	if full && s.mutex.TryLock() {
+		s.touches.replay(s.policy)
+		s.mutex.Unlock()
+	}
+
+	return output, ok
+}
+
+// set returns the ids evicted by the policy, they contain the given id when it is not admitted.
+func (s *shard[K, V]) set(id K, value V, cost int, ttl time.Duration) []K {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.content[id] = value
+
+	if ttl != 0 {
+		s.expirations[id] = time.Now().Add(ttl)
+	} else {
+		delete(s.expirations, id)
+	}
+
+	if s.policy == nil {
+		return nil
+	}
+
+	s.touches.replay(s.policy)
+
+	evicted := s.policy.Add(id, cost)
+
+	for _, evictedID := range evicted {
+		delete(s.content, evictedID)
+		delete(s.expirations, evictedID)
+	}
+
+	return evicted
+}
+
+func (s *shard[K, V]) delete(id K) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.content, id)
+	delete(s.expirations, id)
+
+	if s.policy != nil {
+		s.touches.replay(s.policy)
+		s.policy.Remove(id)
+	}
+}
+
+func (s *shard[K, V]) matching(predicate func(K, V) bool) []K {
+	var output []K
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for id, value := range s.content {
+		if predicate(id, value) {
+			output = append(output, id)
+		}
+	}
+
+	return output
+}
+
+type shardEntry[V any] struct {
+	value      V
+	expiration time.Time
+}
+
+func (s *shard[K, V]) entries() map[K]shardEntry[V] {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	output := make(map[K]shardEntry[V], len(s.content))
+
+	for id, value := range s.content {
+		output[id] = shardEntry[V]{value: value, expiration: s.expirations[id]}
+	}
+
+	return output
+}
+
+func (s *shard[K, V]) len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.content)
+}
+
+func (s *shard[K, V]) expiration(id K) (time.Time, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if _, ok := s.content[id]; !ok {
+		return time.Time{}, false
+	}
+
+	return s.expirations[id], true
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardCount(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		capacity int
+		want     int
+	}{
+		"small": {
+			capacity: 3,
+			want:     1,
+		},
+		"two shards": {
+			capacity: 128,
+			want:     2,
+		},
+		"not a power of two": {
+			capacity: 1000,
+			want:     8,
+		},
+		"capped": {
+			capacity: 1 << 20,
+			want:     maxShards,
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.want, shardCount(testCase.capacity))
+		})
+	}
+}
+
+func TestShardTouch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replayed before eviction", func(t *testing.T) {
+		t.Parallel()
+
+		instance := newShard[int, int](NewLRU[int](2))
+
+		instance.set(1, 1, 1, 0)
+		instance.set(2, 2, 1, 0)
+
+		_, ok := instance.get(1)
+		assert.True(t, ok)
+
+		assert.Equal(t, []int{2}, instance.set(3, 3, 1, 0))
+	})
+
+	t.Run("replayed when full", func(t *testing.T) {
+		t.Parallel()
+
+		instance := newShard[int, int](NewLRU[int](2))
+
+		instance.set(1, 1, 1, 0)
+
+		for range touchBufferSize {
+			instance.get(1)
+		}
+
+		assert.Equal(t, uint64(touchBufferSize), instance.touches.next.Load())
+
+		instance.get(1)
+
+		assert.Equal(t, uint64(0), instance.touches.next.Load())
+	})
+}
+
+const benchmarkCapacity = 10_000
+
+// BenchmarkGet compares a single shard, equivalent to a global lock, with the default sharding.
+func BenchmarkGet(b *testing.B) {
+	for _, procs := range []int{1, 4, 16, 64} {
+		for _, shards := range []int{1, shardCount(benchmarkCapacity)} {
+			b.Run(fmt.Sprintf("procs=%d/shards=%d", procs, shards), func(b *testing.B) {
+				instance := newCache[int, int](benchmarkCapacity, NewLRU[int], shards)
+				go instance.Start(b.Context())
+
+				for i := range benchmarkCapacity {
+					instance.Set(i, i, 0)
+				}
+
+				benchmarkParallel(b, procs, func(i int) {
+					instance.Get(i % benchmarkCapacity)
+				})
+			})
+		}
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	for _, procs := range []int{1, 4, 16, 64} {
+		for _, shards := range []int{1, shardCount(benchmarkCapacity)} {
+			b.Run(fmt.Sprintf("procs=%d/shards=%d", procs, shards), func(b *testing.B) {
+				instance := newCache[int, int](benchmarkCapacity, NewLRU[int], shards)
+				go instance.Start(b.Context())
+
+				benchmarkParallel(b, procs, func(i int) {
+					instance.Set(i%(benchmarkCapacity*2), i, 0)
+				})
+			})
+		}
+	}
+}
+
+func benchmarkParallel(b *testing.B, procs int, action func(int)) {
+	b.Helper()
+
+	previous := runtime.GOMAXPROCS(procs)
+	defer runtime.GOMAXPROCS(previous)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+
+		for pb.Next() {
+			action(i)
+			i += 7
+		}
+	})
+}
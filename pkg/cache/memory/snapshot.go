@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+type snapshotEntry[K comparable] struct {
+	ID         K
+	Expiration time.Time
+	Payload    []byte
+}
+
+// Snapshot writes the entries with their expiration time, values are encoded with the given function. Entries it fails to encode are skipped.
+func (c *Cache[K, V]) Snapshot(writer io.Writer, encode func(K, V) ([]byte, error)) error {
+	encoder := gob.NewEncoder(writer)
+
+	for _, shard := range c.shards {
+		for id, item := range shard.entries() {
+			payload, err := encode(id, item.value)
+			if err != nil {
+				continue
+			}
+
+			if err = encoder.Encode(snapshotEntry[K]{ID: id, Expiration: item.expiration, Payload: payload}); err != nil {
+				return fmt.Errorf("write `%v`: %w", id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Restore sets the entries of a snapshot with their remaining TTL, expired ones and the ones it fails to decode are discarded.
+func (c *Cache[K, V]) Restore(reader io.Reader, decode func(K, []byte) (V, error)) error {
+	decoder := gob.NewDecoder(reader)
+
+	for {
+		var item snapshotEntry[K]
+
+		if err := decoder.Decode(&item); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("read: %w", err)
+		}
+
+		var ttl time.Duration
+
+		if !item.Expiration.IsZero() {
+			if ttl = time.Until(item.Expiration); ttl <= 0 {
+				continue
+			}
+		}
+
+		value, err := decode(item.ID, item.Payload)
+		if err != nil {
+			continue
+		}
+
+		c.Set(item.ID, value, ttl)
+	}
+}
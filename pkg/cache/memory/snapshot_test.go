@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeString(_ string, value string) ([]byte, error) {
+	return []byte(value), nil
+}
+
+func decodeString(_ string, payload []byte) (string, error) {
+	if string(payload) == "invalid" {
+		return "", errors.New("invalid payload")
+	}
+
+	return string(payload), nil
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	instance := New[string, string](0, nil)
+	go instance.Start(t.Context())
+
+	instance.Set("forever", "one", 0)
+	instance.Set("ttl", "two", time.Hour)
+	instance.Set("expired", "three", time.Hour)
+	instance.Set("invalid", "invalid", 0)
+
+	expiredShard := instance.shard("expired")
+	expiredShard.mutex.Lock()
+	expiredShard.expirations["expired"] = time.Now().Add(-time.Second)
+	expiredShard.mutex.Unlock()
+
+	var buffer bytes.Buffer
+
+	assert.NoError(t, instance.Snapshot(&buffer, encodeString))
+
+	restored := New[string, string](0, nil)
+	go restored.Start(t.Context())
+
+	assert.NoError(t, restored.Restore(&buffer, decodeString))
+
+	output := make([]string, 4)
+
+	assert.Equal(t, []string{"expired", "invalid"}, restored.GetAll([]string{"forever", "ttl", "expired", "invalid"}, output))
+	assert.Equal(t, []string{"one", "two", "", ""}, output)
+
+	expiration := restored.shard("ttl").entries()["ttl"].expiration
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiration, time.Second)
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	sketchDepth      = 4
+	sketchMaxCounter = 15
+	sketchMinWidth   = 16
+	sketchMaxWidth   = 1 << 20
+)
+
+// TinyLFU implements W-TinyLFU: new entries go through a small LRU window, then are admitted in the main segmented LRU only if they are estimated more frequent than the entry they would evict.
+type TinyLFU[K comparable] struct {
+	sketch    *sketch[K]
+	window    *LRU[K]
+	probation *LRU[K]
+	protected *LRU[K]
+	capacity  int
+}
+
+// NewTinyLFU gives 1% of the capacity to the window, and 80% of the main segment to the protected entries.
+func NewTinyLFU[K comparable](capacity int) Policy[K] {
+	windowCapacity := max(1, capacity/100)
+	mainCapacity := capacity - windowCapacity
+
+	return &TinyLFU[K]{
+		sketch:    newSketch[K](capacity),
+		window:    newLRU[K](windowCapacity),
+		probation: newLRU[K](mainCapacity),
+		protected: newLRU[K](mainCapacity * 8 / 10),
+		capacity:  capacity,
+	}
+}
+
+func (t *TinyLFU[K]) Add(id K, cost int) []K {
+	t.Remove(id)
+	t.sketch.increment(id)
+
+	if cost > t.capacity {
+		return []K{id}
+	}
+
+	var evicted []K
+
+	for _, candidate := range t.window.add(id, cost) {
+		evicted = append(evicted, t.admit(candidate)...)
+	}
+
+	return evicted
+}
+
+func (t *TinyLFU[K]) Touch(id K) {
+	t.sketch.increment(id)
+
+	switch {
+	case t.window.contains(id):
+		t.window.Touch(id)
+
+	case t.protected.contains(id):
+		t.protected.Touch(id)
+
+	case t.probation.contains(id):
+		item := t.probation.remove(t.probation.index[id])
+
+		for _, demoted := range t.protected.add(item.id, item.cost) {
+			t.probation.push(demoted)
+		}
+	}
+}
+
+func (t *TinyLFU[K]) Remove(id K) {
+	t.window.Remove(id)
+	t.probation.Remove(id)
+	t.protected.Remove(id)
+}
+
+// admit moves the candidate evicted from the window to the main segment, when it is more frequent than the victims it would replace.
+func (t *TinyLFU[K]) admit(candidate entry[K]) []K {
+	mainCapacity := t.probation.capacity
+
+	if t.probation.used+t.protected.used+candidate.cost <= mainCapacity {
+		t.probation.push(candidate)
+
+		return nil
+	}
+
+	frequency := t.sketch.estimate(candidate.id)
+
+	var victims []entry[K]
+	freed := mainCapacity - t.probation.used - t.protected.used
+
+	for _, segment := range []*LRU[K]{t.probation, t.protected} {
+		for element := segment.list.Back(); element != nil && freed < candidate.cost; element = element.Prev() {
+			victim := element.Value.(entry[K])
+
+			if t.sketch.estimate(victim.id) >= frequency {
+				return []K{candidate.id}
+			}
+
+			victims = append(victims, victim)
+			freed += victim.cost
+		}
+	}
+
+	evicted := make([]K, len(victims))
+
+	for index, victim := range victims {
+		t.probation.Remove(victim.id)
+		t.protected.Remove(victim.id)
+
+		evicted[index] = victim.id
+	}
+
+	t.probation.push(candidate)
+
+	return evicted
+}
+
+// sketch is a count-min sketch of counters saturating at 15, halved periodically so old frequencies fade away. It has four counters per entry of capacity to limit collisions.
+type sketch[K comparable] struct {
+	counters  [sketchDepth][]uint8
+	seed      maphash.Seed
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+func newSketch[K comparable](capacity int) *sketch[K] {
+	width := 1 << bits.Len(uint(min(max(capacity*4, sketchMinWidth), sketchMaxWidth)-1))
+
+	output := &sketch[K]{
+		seed:    maphash.MakeSeed(),
+		mask:    uint64(width - 1),
+		resetAt: width * 10,
+	}
+
+	for row := range output.counters {
+		output.counters[row] = make([]uint8, width)
+	}
+
+	return output
+}
+
+func (s *sketch[K]) increment(id K) {
+	hash := maphash.Comparable(s.seed, id)
+
+	for row := range s.counters {
+		if index := s.index(hash, row); s.counters[row][index] < sketchMaxCounter {
+			s.counters[row][index]++
+		}
+	}
+
+	if s.additions++; s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+func (s *sketch[K]) estimate(id K) uint8 {
+	hash := maphash.Comparable(s.seed, id)
+
+	output := uint8(sketchMaxCounter)
+	for row := range s.counters {
+		output = min(output, s.counters[row][s.index(hash, row)])
+	}
+
+	return output
+}
+
+func (s *sketch[K]) index(hash uint64, row int) uint64 {
+	return (hash + uint64(row)*(hash>>32|1)) & s.mask
+}
+
+func (s *sketch[K]) reset() {
+	for row := range s.counters {
+		for index := range s.counters[row] {
+			s.counters[row][index] >>= 1
+		}
+	}
+
+	s.additions /= 2
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type metrics struct {
+	hit         metric.Int64Counter
+	miss        metric.Int64Counter
+	failure     metric.Int64Counter
+	eviction    metric.Int64Counter
+	coalesced   metric.Int64Counter
+	fetch       metric.Float64Histogram
+	attrs       metric.MeasurementOption
+	memoryHit   metric.MeasurementOption
+	redisHit    metric.MeasurementOption
+	decodeError metric.MeasurementOption
+	storeError  metric.MeasurementOption
+}
+
+func newMetrics(provider metric.MeterProvider, name string) (*metrics, error) {
+	meter := provider.Meter("github.com/ViBiOh/httputils/v4/pkg/cache")
+
+	var output metrics
+	var err error
+
+	if output.hit, err = meter.Int64Counter("cache.hit"); err != nil {
+		return nil, fmt.Errorf("create hit counter: %w", err)
+	}
+
+	if output.miss, err = meter.Int64Counter("cache.miss"); err != nil {
+		return nil, fmt.Errorf("create miss counter: %w", err)
+	}
+
+	if output.failure, err = meter.Int64Counter("cache.error"); err != nil {
+		return nil, fmt.Errorf("create error counter: %w", err)
+	}
+
+	if output.eviction, err = meter.Int64Counter("cache.eviction"); err != nil {
+		return nil, fmt.Errorf("create eviction counter: %w", err)
+	}
+
+	if output.coalesced, err = meter.Int64Counter("cache.coalesced"); err != nil {
+		return nil, fmt.Errorf("create coalesced counter: %w", err)
+	}
+
+	if output.fetch, err = meter.Float64Histogram("cache.fetch.duration", metric.WithUnit("s")); err != nil {
+		return nil, fmt.Errorf("create fetch histogram: %w", err)
+	}
+
+	nameAttr := attribute.String("name", name)
+
+	output.attrs = metric.WithAttributes(nameAttr)
+	output.memoryHit = metric.WithAttributes(nameAttr, attribute.String("layer", "memory"))
+	output.redisHit = metric.WithAttributes(nameAttr, attribute.String("layer", "redis"))
+	output.decodeError = metric.WithAttributes(nameAttr, attribute.String("type", "decode"))
+	output.storeError = metric.WithAttributes(nameAttr, attribute.String("type", "store"))
+
+	return &output, nil
+}
+
+func (m *metrics) recordMemoryHit(ctx context.Context, count int) {
+	if m == nil || count == 0 {
+		return
+	}
+
+	m.hit.Add(ctx, int64(count), m.memoryHit)
+}
+
+func (m *metrics) recordRedisHit(ctx context.Context, count int) {
+	if m == nil || count == 0 {
+		return
+	}
+
+	m.hit.Add(ctx, int64(count), m.redisHit)
+}
+
+func (m *metrics) recordMiss(ctx context.Context, count int) {
+	if m == nil || count == 0 {
+		return
+	}
+
+	m.miss.Add(ctx, int64(count), m.attrs)
+}
+
+func (m *metrics) recordDecodeError(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.failure.Add(ctx, 1, m.decodeError)
+}
+
+func (m *metrics) recordStoreError(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.failure.Add(ctx, 1, m.storeError)
+}
+
+func (m *metrics) recordEviction(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.eviction.Add(ctx, 1, m.attrs)
+}
+
+func (m *metrics) recordCoalesced(ctx context.Context, count int) {
+	if m == nil || count == 0 {
+		return
+	}
+
+	m.coalesced.Add(ctx, int64(count), m.attrs)
+}
+
+func (m *metrics) recordFetch(ctx context.Context, start time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.fetch.Record(ctx, time.Since(start).Seconds(), m.attrs)
+}
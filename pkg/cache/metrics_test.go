@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	reader := sdkmetric.NewManualReader()
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().Load(gomock.Any(), "1").Return([]byte("cached"), nil)
+	mockRedisClient.EXPECT().Load(gomock.Any(), "2").Return(nil, nil)
+	mockRedisClient.EXPECT().Store(gomock.Any(), "2", gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+		return "fetched", nil
+	}, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), nil).WithSerializer(StringSerializer{})
+
+	_, err := instance.Get(context.Background(), 1)
+	assert.NoError(t, err)
+
+	_, err = instance.Get(context.Background(), 2)
+	assert.NoError(t, err)
+
+	instance.recordCoalesced(context.Background(), 2)
+
+	var data metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &data))
+
+	got := make(map[string]int64)
+
+	for _, scope := range data.ScopeMetrics {
+		for _, item := range scope.Metrics {
+			switch content := item.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range content.DataPoints {
+					layer, _ := point.Attributes.Value(attribute.Key("layer"))
+					got[item.Name+layer.AsString()] += point.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range content.DataPoints {
+					name, _ := point.Attributes.Value(attribute.Key("name"))
+					assert.Equal(t, "test", name.AsString())
+
+					got[item.Name] += int64(point.Count)
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]int64{
+		"cache.hitredis":       1,
+		"cache.miss":           1,
+		"cache.coalesced":      2,
+		"cache.fetch.duration": 1,
+	}, got)
+}
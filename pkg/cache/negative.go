@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ErrCachedNotFound = fmt.Errorf("cached: %w", model.ErrNotFound)
+
+// isNotFound checks the flag of the envelope, a negative TTL implying envelopes.
+func (c *Cache[K, V]) isNotFound(content []byte) bool {
+	if c.negativeTTL == 0 {
+		return false
+	}
+
+	item, ok := unwrapEnvelope(content)
+
+	return ok && item.flags&envelopeNotFound != 0
+}
+
+func (c *Cache[K, V]) shouldStoreNotFound(err error) bool {
+	return c.negativeTTL != 0 && c.write != nil && errors.Is(err, model.ErrNotFound)
+}
+
+func (c *Cache[K, V]) storeNotFound(ctx context.Context, id K) (err error) {
+	ctx, end := telemetry.StartSpan(ctx, c.tracer, "store_not_found", trace.WithSpanKind(trace.SpanKindInternal))
+	defer end(&err)
+
+	key := c.toKey(id)
+
+	if err = c.write.Store(ctx, key, newEnvelope(c.version, time.Now(), envelopeNotFound, nil), c.negativeTTL); err != nil {
+		c.metrics.recordStoreError(ctx)
+
+		return fmt.Errorf("store not found for key `%s`: %w", key, err)
+	}
+
+	return nil
+}
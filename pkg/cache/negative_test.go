@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetNegativeTTL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cached", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+
+		mockRedisClient := mocks.NewRedisClient(ctrl)
+		mockRedisClient.EXPECT().Enabled().Return(true)
+		mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(newEnvelope(0, time.Now(), envelopeNotFound, nil), nil)
+
+		instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+			return "", errors.New("should not be called")
+		}, nil, nil).WithNegativeTTL(time.Second)
+
+		_, err := instance.Get(context.Background(), 8000)
+
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.ErrorIs(t, err, ErrCachedNotFound)
+	})
+
+	t.Run("store", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+
+		stored := make(chan struct{})
+
+		mockRedisClient := mocks.NewRedisClient(ctrl)
+		mockRedisClient.EXPECT().Enabled().Return(true)
+		mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(nil, nil)
+		mockRedisClient.EXPECT().Store(gomock.Any(), "8000", gomock.Any(), time.Second).DoAndReturn(func(_ context.Context, _ string, value any, _ time.Duration) error {
+			item, ok := unwrapEnvelope(value.([]byte))
+			assert.True(t, ok)
+			assert.Equal(t, envelopeNotFound, item.flags)
+
+			close(stored)
+
+			return nil
+		})
+
+		instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+			return "", model.WrapNotFound(errors.New("unknown id"))
+		}, nil, nil).WithNegativeTTL(time.Second)
+
+		_, err := instance.Get(context.Background(), 8000)
+
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		select {
+		case <-stored:
+		case <-time.After(time.Second):
+			t.Error("not found not stored")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+
+		mockRedisClient := mocks.NewRedisClient(ctrl)
+		mockRedisClient.EXPECT().Enabled().Return(true)
+		mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(nil, nil)
+
+		instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+			return "", model.WrapNotFound(errors.New("unknown id"))
+		}, nil, nil)
+
+		_, err := instance.Get(context.Background(), 8000)
+
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.NotErrorIs(t, err, ErrCachedNotFound)
+	})
+}
+
+func TestListNegativeTTL(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().LoadMany(gomock.Any(), "1", "2").Return([]string{string(wrapEnvelope(0, time.Now(), []byte("one"))), string(newEnvelope(0, time.Now(), envelopeNotFound, nil))}, nil)
+
+	instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+		return "", errors.New("should not be called")
+	}, nil, nil).WithSerializer(StringSerializer{}).WithNegativeTTL(time.Second)
+
+	var notFoundIDs []int
+
+	got, err := instance.List(context.Background(), func(_ context.Context, id int, err error) error {
+		if errors.Is(err, model.ErrNotFound) {
+			notFoundIDs = append(notFoundIDs, id)
+		}
+
+		return nil
+	}, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"one", ""}, got)
+	assert.Equal(t, []int{2}, notFoundIDs)
+}
+
+func TestGetNegativeTTLRawValue(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(wrapEnvelope(0, time.Now(), []byte("\xfe")), nil)
+
+	instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+		return "", errors.New("should not be called")
+	}, nil, nil).WithSerializer(StringSerializer{}).WithNegativeTTL(time.Second)
+
+	got, err := instance.Get(context.Background(), 8000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "\xfe", got)
+}
@@ -0,0 +1,266 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// extendQueuePrefix and refreshQueuePrefix keep TTL extensions and refreshes apart from stores of the same key in the queue.
+const (
+	extendQueuePrefix  = "extend "
+	refreshQueuePrefix = "refresh "
+)
+
+type QueuePolicy int
+
+const (
+	// DropWhenFull discards the write when the queue is full, the value is fetched again on the next miss.
+	DropWhenFull QueuePolicy = iota
+	// BlockWhenFull waits for a free slot, slowing down the caller. Writes enqueued from a queued task, like the store of a refresh, are dropped instead, a worker waiting for itself would never free a slot.
+	BlockWhenFull
+)
+
+type queueTaskKey struct{}
+
+func isQueueTask(ctx context.Context) bool {
+	boolValue, _ := ctx.Value(queueTaskKey{}).(bool)
+
+	return boolValue
+}
+
+type queuedTask struct {
+	ctx    context.Context
+	action func(context.Context) error
+	key    string
+}
+
+// WriteQueue runs the background writes of caches with a fixed number of workers. Pending writes of the same key are coalesced, only the last one is done.
+type WriteQueue struct {
+	tasks     chan queuedTask
+	slots     chan struct{}
+	closed    chan struct{}
+	done      chan struct{}
+	pending   map[string]queuedTask
+	metrics   *queueMetrics
+	mutex     sync.Mutex
+	workers   int
+	policy    QueuePolicy
+	isClosing bool
+}
+
+// NewWriteQueue creates a queue of the given size, it has to be started to process writes. It can be shared between caches, see Cache.WithWriteQueue.
+func NewWriteQueue(size, workers int, policy QueuePolicy, meterProvider metric.MeterProvider) *WriteQueue {
+	queue := &WriteQueue{
+		tasks:   make(chan queuedTask, size),
+		slots:   make(chan struct{}, size),
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+		pending: make(map[string]queuedTask),
+		workers: max(1, workers),
+		policy:  policy,
+	}
+
+	if meterProvider != nil {
+		var err error
+
+		if queue.metrics, err = newQueueMetrics(meterProvider, queue); err != nil {
+			slog.LogAttrs(context.Background(), slog.LevelError, "init write queue metrics", slog.Any("error", err))
+		}
+	}
+
+	return queue
+}
+
+// Start runs the workers until the context is done, then drains the pending writes before closing Done.
+func (q *WriteQueue) Start(ctx context.Context) {
+	defer close(q.done)
+
+	var wg sync.WaitGroup
+
+	for range q.workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			q.work()
+		}()
+	}
+
+	<-ctx.Done()
+
+	q.mutex.Lock()
+	q.isClosing = true
+	close(q.closed)
+	close(q.tasks)
+	q.mutex.Unlock()
+
+	wg.Wait()
+}
+
+func (q *WriteQueue) Done() <-chan struct{} {
+	return q.done
+}
+
+// Len returns the number of pending writes.
+func (q *WriteQueue) Len() int {
+	return len(q.slots)
+}
+
+// Enqueue adds the action to the queue, replacing the pending one of the same key. An empty key is never coalesced. It returns false when the write is dropped.
+func (q *WriteQueue) Enqueue(ctx context.Context, key string, action func(context.Context) error) bool {
+	task := queuedTask{ctx: context.WithoutCancel(ctx), key: key, action: action}
+
+	if q.replace(task) {
+		q.metrics.recordCoalesced(ctx)
+
+		return true
+	}
+
+	if !q.acquire(ctx) {
+		q.metrics.recordDropped(ctx)
+
+		return false
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.isClosing {
+		<-q.slots
+		q.metrics.recordDropped(ctx)
+
+		return false
+	}
+
+	if len(key) != 0 {
+		if _, ok := q.pending[key]; ok {
+			q.pending[key] = task
+			<-q.slots
+			q.metrics.recordCoalesced(ctx)
+
+			return true
+		}
+
+		q.pending[key] = task
+	}
+
+	// A slot is acquired for each task in the channel, so it never blocks.
+	q.tasks <- task
+	q.metrics.recordEnqueued(ctx)
+
+	return true
+}
+
+func (q *WriteQueue) replace(task queuedTask) bool {
+	if len(task.key) == 0 {
+		return false
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, ok := q.pending[task.key]; !ok || q.isClosing {
+		return false
+	}
+
+	q.pending[task.key] = task
+
+	return true
+}
+
+func (q *WriteQueue) acquire(ctx context.Context) bool {
+	if q.policy == BlockWhenFull && !isQueueTask(ctx) {
+		select {
+		case q.slots <- struct{}{}:
+			return true
+		case <-ctx.Done():
+			return false
+		case <-q.closed:
+			return false
+		}
+	}
+
+	select {
+	case q.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *WriteQueue) work() {
+	for task := range q.tasks {
+		if len(task.key) != 0 {
+			q.mutex.Lock()
+			task = q.pending[task.key]
+			delete(q.pending, task.key)
+			q.mutex.Unlock()
+		}
+
+		<-q.slots
+
+		doInBackground(context.WithValue(task.ctx, queueTaskKey{}, true), task.action)
+	}
+}
+
+type queueMetrics struct {
+	counter   metric.Int64Counter
+	enqueued  metric.MeasurementOption
+	coalesced metric.MeasurementOption
+	dropped   metric.MeasurementOption
+}
+
+func newQueueMetrics(provider metric.MeterProvider, queue *WriteQueue) (*queueMetrics, error) {
+	meter := provider.Meter("github.com/ViBiOh/httputils/v4/pkg/cache")
+
+	var output queueMetrics
+	var err error
+
+	if output.counter, err = meter.Int64Counter("cache.queue.write"); err != nil {
+		return nil, fmt.Errorf("create write counter: %w", err)
+	}
+
+	if _, err = meter.Int64ObservableGauge("cache.queue.size", metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+		observer.Observe(int64(queue.Len()))
+
+		return nil
+	})); err != nil {
+		return nil, fmt.Errorf("create size gauge: %w", err)
+	}
+
+	output.enqueued = metric.WithAttributes(attribute.String("state", "enqueued"))
+	output.coalesced = metric.WithAttributes(attribute.String("state", "coalesced"))
+	output.dropped = metric.WithAttributes(attribute.String("state", "dropped"))
+
+	return &output, nil
+}
+
+func (m *queueMetrics) recordEnqueued(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.counter.Add(ctx, 1, m.enqueued)
+}
+
+func (m *queueMetrics) recordCoalesced(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.counter.Add(ctx, 1, m.coalesced)
+}
+
+func (m *queueMetrics) recordDropped(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.counter.Add(ctx, 1, m.dropped)
+}
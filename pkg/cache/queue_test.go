@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteQueue(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	var written []string
+
+	write := func(value string) func(context.Context) error {
+		return func(_ context.Context) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			written = append(written, value)
+
+			return nil
+		}
+	}
+
+	instance := NewWriteQueue(2, 1, DropWhenFull, nil)
+
+	assert.True(t, instance.Enqueue(context.Background(), "1", write("first")))
+	assert.True(t, instance.Enqueue(context.Background(), "1", write("second")))
+	assert.True(t, instance.Enqueue(context.Background(), "", write("many")))
+	assert.False(t, instance.Enqueue(context.Background(), "2", write("dropped")))
+	assert.True(t, instance.Enqueue(context.Background(), "1", write("third")))
+	assert.Equal(t, 2, instance.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	instance.Start(ctx)
+
+	<-instance.Done()
+
+	assert.Equal(t, []string{"third", "many"}, written)
+	assert.False(t, instance.Enqueue(context.Background(), "1", write("closed")))
+}
+
+func TestWriteQueueBlock(t *testing.T) {
+	t.Parallel()
+
+	instance := NewWriteQueue(1, 1, BlockWhenFull, nil)
+
+	noop := func(_ context.Context) error {
+		return nil
+	}
+
+	assert.True(t, instance.Enqueue(context.Background(), "1", noop))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	assert.False(t, instance.Enqueue(ctx, "2", noop))
+
+	startCtx, stop := context.WithCancel(context.Background())
+	go instance.Start(startCtx)
+
+	assert.True(t, instance.Enqueue(context.Background(), "2", noop))
+
+	stop()
+	<-instance.Done()
+
+	assert.Equal(t, 0, instance.Len())
+}
+
+func TestWriteQueueBlockFromTask(t *testing.T) {
+	t.Parallel()
+
+	instance := NewWriteQueue(1, 1, BlockWhenFull, nil)
+
+	noop := func(_ context.Context) error {
+		return nil
+	}
+
+	results := make(chan bool, 2)
+
+	assert.True(t, instance.Enqueue(context.Background(), "refresh", func(ctx context.Context) error {
+		results <- instance.Enqueue(ctx, "1", noop)
+		results <- instance.Enqueue(ctx, "2", noop)
+
+		return nil
+	}))
+
+	startCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	go instance.Start(startCtx)
+
+	for _, expected := range []bool{true, false} {
+		select {
+		case result := <-results:
+			assert.Equal(t, expected, result)
+		case <-time.After(time.Second):
+			t.Fatal("enqueue from a queued task is blocked")
+		}
+	}
+
+	stop()
+	<-instance.Done()
+}
+
+func TestBackgroundSharedQueue(t *testing.T) {
+	t.Parallel()
+
+	queue := NewWriteQueue(4, 1, DropWhenFull, nil)
+
+	users := New[int, string]("users", nil, strconv.Itoa, nil, nil, nil).WithWriteQueue(queue)
+	items := New[int, string]("items", nil, strconv.Itoa, nil, nil, nil).WithWriteQueue(queue)
+
+	noop := func(context.Context) error { return nil }
+
+	users.background(context.Background(), "1", noop)
+	items.background(context.Background(), "1", noop)
+	users.background(context.Background(), "1", noop)
+
+	assert.Equal(t, 2, queue.Len())
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/model"
+)
+
+type inspectable interface {
+	info() Info
+	inspect(ctx context.Context, id string) (KeyInfo, error)
+	evict(ctx context.Context, id string) error
+	evictTag(ctx context.Context, tag string) error
+}
+
+type Info struct {
+	Name         string `json:"name"`
+	TTL          string `json:"ttl,omitempty"`
+	SoftTTL      string `json:"softTTL,omitempty"`
+	StaleIfError string `json:"staleIfError,omitempty"`
+	NegativeTTL  string `json:"negativeTTL,omitempty"`
+	LockTTL      string `json:"lockTTL,omitempty"`
+	MemorySize   int    `json:"memorySize"`
+	Version      uint32 `json:"version,omitempty"`
+	Memory       bool   `json:"memory"`
+	Redis        bool   `json:"redis"`
+	Tags         bool   `json:"tags"`
+}
+
+type KeyInfo struct {
+	Key    string   `json:"key"`
+	Memory KeyLayer `json:"memory"`
+	Redis  KeyLayer `json:"redis"`
+}
+
+type KeyLayer struct {
+	TTL     string `json:"ttl,omitempty"`
+	Present bool   `json:"present"`
+}
+
+// Registry lists caches for introspection, see Handler.
+type Registry struct {
+	caches map[string]inspectable
+	mutex  sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		caches: make(map[string]inspectable),
+	}
+}
+
+func (r *Registry) register(name string, cache inspectable) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.caches[name] = cache
+}
+
+func (r *Registry) get(name string) (inspectable, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	cache, ok := r.caches[name]
+
+	return cache, ok
+}
+
+func (r *Registry) infos() []Info {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	output := make([]Info, 0, len(r.caches))
+	for _, cache := range r.caches {
+		output = append(output, cache.info())
+	}
+
+	slices.SortFunc(output, func(a, b Info) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return output
+}
+
+// WithRegistry registers the cache under its name, parseID converts the id of the introspection requests.
+func (c *Cache[K, V]) WithRegistry(registry *Registry, parseID func(string) (K, error)) *Cache[K, V] {
+	c.parseID = parseID
+
+	registry.register(c.name, c)
+
+	return c
+}
+
+func (c *Cache[K, V]) info() Info {
+	output := Info{
+		Name:         c.name,
+		TTL:          formatDuration(c.ttl),
+		SoftTTL:      formatDuration(c.softTTL),
+		StaleIfError: formatDuration(c.staleIfError),
+		NegativeTTL:  formatDuration(c.negativeTTL),
+		LockTTL:      formatDuration(c.lockTTL),
+		Version:      c.version,
+		Memory:       c.memory != nil,
+		Redis:        c.read != nil,
+		Tags:         c.tagger != nil,
+	}
+
+	if c.memory != nil {
+		output.MemorySize = c.memory.Len()
+	}
+
+	return output
+}
+
+func (c *Cache[K, V]) inspect(ctx context.Context, rawID string) (KeyInfo, error) {
+	id, err := c.parseID(rawID)
+	if err != nil {
+		return KeyInfo{}, model.WrapInvalid(fmt.Errorf("parse id: %w", err))
+	}
+
+	output := KeyInfo{
+		Key: c.toKey(id),
+	}
+
+	if c.memory != nil {
+		var expiration time.Time
+
+		if expiration, output.Memory.Present = c.memory.Expiration(id); !expiration.IsZero() {
+			output.Memory.TTL = formatDuration(time.Until(expiration))
+		}
+	}
+
+	if c.read != nil {
+		var ttl time.Duration
+
+		if ttl, output.Redis.Present, err = c.read.TTL(ctx, output.Key); err != nil {
+			return output, fmt.Errorf("redis ttl: %w", err)
+		}
+
+		output.Redis.TTL = formatDuration(ttl)
+	}
+
+	return output, nil
+}
+
+func (c *Cache[K, V]) evict(ctx context.Context, rawID string) error {
+	id, err := c.parseID(rawID)
+	if err != nil {
+		return model.WrapInvalid(fmt.Errorf("parse id: %w", err))
+	}
+
+	return c.EvictOnSuccess(ctx, id, nil)
+}
+
+func (c *Cache[K, V]) evictTag(ctx context.Context, tag string) error {
+	return c.EvictTag(ctx, tag)
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration == 0 {
+		return ""
+	}
+
+	return duration.String()
+}
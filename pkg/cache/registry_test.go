@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/ViBiOh/httputils/v4/pkg/request"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegistryHandler(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+
+	signed := func(method, target string) *http.Request {
+		req := httptest.NewRequest(method, target, nil)
+		request.AddSignature(req, time.Now(), "test", secret, nil)
+
+		return req
+	}
+
+	cases := map[string]struct {
+		request    *http.Request
+		secret     []byte
+		setup      func(*mocks.RedisClient)
+		want       string
+		wantStatus int
+	}{
+		"list": {
+			request:    httptest.NewRequest(http.MethodGet, "/", nil),
+			want:       `[{"name":"test","ttl":"1m0s","memorySize":0,"memory":false,"redis":true,"tags":false}]`,
+			wantStatus: http.StatusOK,
+		},
+		"inspect": {
+			request: httptest.NewRequest(http.MethodGet, "/test/keys/8000", nil),
+			setup: func(mockRedisClient *mocks.RedisClient) {
+				mockRedisClient.EXPECT().TTL(gomock.Any(), "8000").Return(time.Second*30, true, nil)
+			},
+			want:       `{"key":"8000","memory":{"present":false},"redis":{"ttl":"30s","present":true}}`,
+			wantStatus: http.StatusOK,
+		},
+		"unknown cache": {
+			request:    httptest.NewRequest(http.MethodGet, "/unknown/keys/8000", nil),
+			want:       "unknown cache `unknown`",
+			wantStatus: http.StatusNotFound,
+		},
+		"invalid id": {
+			request:    httptest.NewRequest(http.MethodGet, "/test/keys/abc", nil),
+			want:       "parse id",
+			wantStatus: http.StatusBadRequest,
+		},
+		"eviction disabled": {
+			request:    signed(http.MethodDelete, "/test/keys/8000"),
+			wantStatus: http.StatusForbidden,
+		},
+		"unsigned": {
+			request:    httptest.NewRequest(http.MethodDelete, "/test/keys/8000", nil),
+			secret:     secret,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"evict": {
+			request: signed(http.MethodDelete, "/test/keys/8000"),
+			secret:  secret,
+			setup: func(mockRedisClient *mocks.RedisClient) {
+				mockRedisClient.EXPECT().Delete(gomock.Any(), "8000").Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		"evict tag": {
+			request: signed(http.MethodDelete, "/test/tags/users"),
+			secret:  secret,
+			setup: func(mockRedisClient *mocks.RedisClient) {
+				mockRedisClient.EXPECT().DeleteTag(gomock.Any(), "tag:test:users").Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRedisClient := mocks.NewRedisClient(ctrl)
+			mockRedisClient.EXPECT().Enabled().Return(true)
+
+			if testCase.setup != nil {
+				testCase.setup(mockRedisClient)
+			}
+
+			registry := NewRegistry()
+
+			New("test", mockRedisClient, strconv.Itoa, noFetchString, nil, nil).
+				WithTTL(time.Minute).
+				WithRegistry(registry, strconv.Atoi)
+
+			writer := httptest.NewRecorder()
+			registry.Handler(testCase.secret).ServeHTTP(writer, testCase.request)
+
+			assert.Equal(t, testCase.wantStatus, writer.Code)
+
+			body, _ := request.ReadBodyResponse(writer.Result())
+			assert.Contains(t, string(body), testCase.want)
+		})
+	}
+}
+
+func TestRegistryInfos(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+
+	New("second", nil, strconv.Itoa, noFetchString, nil, nil).WithRegistry(registry, strconv.Atoi)
+	New("first", nil, strconv.Itoa, noFetchString, nil, nil).WithClientSideCaching(context.Background(), "first", 10).WithRegistry(registry, strconv.Atoi)
+
+	infos := registry.infos()
+
+	assert.Equal(t, []string{"first", "second"}, []string{infos[0].Name, infos[1].Name})
+	assert.True(t, infos[0].Memory)
+	assert.False(t, infos[1].Redis)
+}
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"bytes"
+	"encoding/gob"
 	"encoding/json"
 )
 
@@ -29,3 +31,18 @@ func (StringSerializer) Encode(payload string) ([]byte, error) {
 func (StringSerializer) Decode(payload []byte) (string, error) {
 	return string(payload), nil
 }
+
+type GobSerializer[V any] struct{}
+
+func (GobSerializer[V]) Encode(payload V) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	err := gob.NewEncoder(&buffer).Encode(payload)
+
+	return buffer.Bytes(), err
+}
+
+func (GobSerializer[V]) Decode(payload []byte) (V, error) {
+	var content V
+	return content, gob.NewDecoder(bytes.NewReader(payload)).Decode(&content)
+}
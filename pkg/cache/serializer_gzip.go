@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// gzipMagic starts every gzip stream. A payload starting with it is always compressed, so any other content is a raw payload, e.g. written before compression was enabled.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// gzipMaxSize bounds the decompressed payload, against a compressed payload expanding without limit.
+const gzipMaxSize = 64 << 20
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+type GzipSerializer[V any] struct {
+	serializer Serializer[V]
+	threshold  int
+}
+
+func NewGzipSerializer[V any](serializer Serializer[V], threshold int) GzipSerializer[V] {
+	return GzipSerializer[V]{
+		serializer: serializer,
+		threshold:  threshold,
+	}
+}
+
+func (gs GzipSerializer[V]) Encode(value V) ([]byte, error) {
+	payload, err := gs.serializer.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) < gs.threshold && !bytes.HasPrefix(payload, gzipMagic) {
+		return payload, nil
+	}
+
+	var buffer bytes.Buffer
+
+	writer := gzipWriterPool.Get().(*gzip.Writer)
+	defer gzipWriterPool.Put(writer)
+
+	writer.Reset(&buffer)
+
+	if _, err = writer.Write(payload); err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, fmt.Errorf("close gzip: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Decode decompresses a gzip stream, and decodes any other content as it is.
+func (gs GzipSerializer[V]) Decode(content []byte) (V, error) {
+	if !bytes.HasPrefix(content, gzipMagic) {
+		return gs.serializer.Decode(content)
+	}
+
+	var output V
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return output, fmt.Errorf("gunzip: %w", err)
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(reader, gzipMaxSize+1))
+	if closeErr := reader.Close(); closeErr != nil {
+		err = errors.Join(err, closeErr)
+	}
+
+	if err != nil {
+		return output, fmt.Errorf("read gunzip: %w", err)
+	}
+
+	if len(payload) > gzipMaxSize {
+		return output, fmt.Errorf("gunzip payload exceeds %d bytes", gzipMaxSize)
+	}
+
+	return gs.serializer.Decode(payload)
+}
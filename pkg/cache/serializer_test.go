@@ -7,6 +7,88 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestGzipSerializer(t *testing.T) {
+	t.Parallel()
+
+	content := getRepository(t)
+
+	raw, err := cache.JSONSerializer[Repository]{}.Encode(content)
+	assert.NoError(t, err)
+
+	cases := map[string]struct {
+		threshold  int
+		compressed bool
+	}{
+		"below threshold": {
+			len(raw) + 1,
+			false,
+		},
+		"above threshold": {
+			128,
+			true,
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			instance := cache.NewGzipSerializer(cache.JSONSerializer[Repository]{}, testCase.threshold)
+
+			payload, err := instance.Encode(content)
+			assert.NoError(t, err)
+
+			if testCase.compressed {
+				assert.Less(t, len(payload), len(raw))
+			} else {
+				assert.Equal(t, raw, payload)
+			}
+
+			got, err := instance.Decode(payload)
+			assert.NoError(t, err)
+			assert.Equal(t, content, got)
+
+			got, err = instance.Decode(raw)
+			assert.NoError(t, err)
+			assert.Equal(t, content, got)
+		})
+	}
+}
+
+func TestGzipSerializerRawHeader(t *testing.T) {
+	t.Parallel()
+
+	instance := cache.NewGzipSerializer[string](cache.StringSerializer{}, 1024)
+
+	// Starts like a gzip stream, so it's compressed even below the threshold.
+	value := "\x1f\x8bnot compressed"
+
+	payload, err := instance.Encode(value)
+	assert.NoError(t, err)
+	assert.NotEqual(t, []byte(value), payload)
+
+	got, err := instance.Decode(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, value, got)
+}
+
+func TestGobSerializer(t *testing.T) {
+	t.Parallel()
+
+	content := getRepository(t)
+	content.MirrorURL = nil
+	content.Topics = nil
+
+	var instance cache.GobSerializer[Repository]
+
+	payload, err := instance.Encode(content)
+	assert.NoError(t, err)
+
+	got, err := instance.Decode(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, content, got)
+}
+
 func BenchmarkJSONDeserializer(b *testing.B) {
 	content := getRepository(b)
 
@@ -30,3 +112,16 @@ func BenchmarkStringDeserializer(b *testing.B) {
 		_, _ = instance.Decode(payload)
 	}
 }
+
+func BenchmarkGzipDeserializer(b *testing.B) {
+	content := getRepository(b)
+
+	instance := cache.NewGzipSerializer(cache.JSONSerializer[Repository]{}, 0)
+
+	payload, err := instance.Encode(content)
+	assert.NoError(b, err)
+
+	for b.Loop() {
+		_, _ = instance.Decode(payload)
+	}
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// WithMemorySnapshot restores the memory cache from the file, and writes it back when the context is done. It has to be called after WithClientSideCaching and WithSerializer.
+func (c *Cache[K, V]) WithMemorySnapshot(ctx context.Context, filename string) *Cache[K, V] {
+	if c.memory == nil {
+		return c
+	}
+
+	if err := c.restoreMemory(filename); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "restore memory snapshot", slog.String("filename", filename), slog.Any("error", err))
+	}
+
+	done := make(chan struct{})
+	c.done = done
+
+	go func() {
+		defer close(done)
+
+		<-ctx.Done()
+
+		if err := c.saveMemory(filename); err != nil {
+			slog.LogAttrs(context.WithoutCancel(ctx), slog.LevelError, "save memory snapshot", slog.String("filename", filename), slog.Any("error", err))
+		}
+	}()
+
+	return c
+}
+
+// Done is closed once the memory snapshot is written, it's already closed without snapshot.
+func (c *Cache[K, V]) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Cache[K, V]) restoreMemory(filename string) (err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("open: %w", err)
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+
+	return c.memory.Restore(file, c.decodeSnapshot)
+}
+
+// decodeSnapshot discards the values without envelope or of another version.
+func (c *Cache[K, V]) decodeSnapshot(id K, content []byte) (value V, err error) {
+	item, ok := unwrapEnvelope(content)
+	if !ok {
+		err = errNoEnvelope
+	} else if err = checkVersion(c.version, item.version); err == nil {
+		value, err = c.serializer.Decode(item.payload)
+	}
+
+	if err != nil {
+		slog.LogAttrs(context.Background(), slog.LevelDebug, "skip memory snapshot entry", slog.Any("id", id), slog.Any("error", err))
+	}
+
+	return value, err
+}
+
+// encodeSnapshot always wraps the value in an envelope, so a restore after a version change discards it.
+func (c *Cache[K, V]) encodeSnapshot(id K, value V) ([]byte, error) {
+	payload, err := c.serializer.Encode(value)
+	if err != nil {
+		slog.LogAttrs(context.Background(), slog.LevelError, "encode memory snapshot entry", slog.Any("id", id), slog.Any("error", err))
+
+		return nil, err
+	}
+
+	return wrapEnvelope(c.version, time.Now(), payload), nil
+}
+
+// saveMemory writes to a temporary file first, so a crash never leaves a truncated snapshot.
+func (c *Cache[K, V]) saveMemory(filename string) (err error) {
+	temporary := filename + ".tmp"
+
+	file, err := os.Create(temporary)
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	if err = c.memory.Snapshot(file, c.encodeSnapshot); err != nil {
+		err = errors.Join(err, file.Close())
+	} else {
+		err = file.Close()
+	}
+
+	if err != nil {
+		return errors.Join(err, os.Remove(temporary))
+	}
+
+	if err = os.Rename(temporary, filename); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+
+	return nil
+}
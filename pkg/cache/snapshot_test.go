@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemorySnapshot(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "snapshot.gob")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	instance := New("test", nil, strconv.Itoa, noFetchString, nil, nil).
+		WithSerializer(StringSerializer{}).
+		WithClientSideCaching(ctx, "test", 10).
+		WithMemorySnapshot(ctx, filename)
+
+	assert.NoError(t, instance.Store(ctx, 8000, "cached"))
+
+	cancel()
+
+	select {
+	case <-instance.Done():
+	case <-time.After(time.Second):
+		t.Fatal("snapshot not written")
+	}
+
+	restored := New("test", nil, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+		return "fetched", nil
+	}, nil, nil).
+		WithSerializer(StringSerializer{}).
+		WithClientSideCaching(t.Context(), "test", 10).
+		WithMemorySnapshot(t.Context(), filename)
+
+	got, err := restored.Get(context.Background(), 8000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", got)
+
+	upgraded := New("test", nil, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+		return "fetched", nil
+	}, nil, nil).
+		WithSerializer(StringSerializer{}).
+		WithVersion(2).
+		WithClientSideCaching(t.Context(), "test", 10).
+		WithMemorySnapshot(t.Context(), filename)
+
+	got, err = upgraded.Get(context.Background(), 8000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "fetched", got)
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/model"
+)
+
+type freshness int
+
+const (
+	fresh freshness = iota
+	stale
+	expired
+)
+
+func (c *Cache[K, V]) freshness(writtenAt time.Time, ttl time.Duration) freshness {
+	if writtenAt.IsZero() {
+		return fresh
+	}
+
+	age := time.Since(writtenAt)
+
+	if ttl > 0 && age >= ttl {
+		return expired
+	}
+
+	if c.softTTL != 0 && age >= c.softTTL {
+		return stale
+	}
+
+	return fresh
+}
+
+func (c *Cache[K, V]) servableOnError(writtenAt time.Time, ttl time.Duration) bool {
+	return c.staleIfError != 0 && time.Since(writtenAt) < ttl+c.staleIfError
+}
+
+// entryTTL is the TTL of the given value, a negative duration means the value must not be stored.
+func (c *Cache[K, V]) entryTTL(value V) time.Duration {
+	if c.ttlFunc == nil {
+		return c.ttl
+	}
+
+	if ttl := c.ttlFunc(value); ttl != 0 {
+		return ttl
+	}
+
+	return c.ttl
+}
+
+// freshTTL is the duration a value written with the given TTL can be served without revalidation.
+func (c *Cache[K, V]) freshTTL(writtenAt time.Time, ttl time.Duration) time.Duration {
+	if c.softTTL != 0 && (ttl == 0 || c.softTTL < ttl) {
+		ttl = c.softTTL
+	}
+
+	if ttl == 0 || writtenAt.IsZero() {
+		return ttl
+	}
+
+	return ttl - time.Since(writtenAt)
+}
+
+// storageTTL keeps values in Redis during the stale-if-error window.
+func (c *Cache[K, V]) storageTTL(ttl time.Duration) time.Duration {
+	if ttl == 0 {
+		return 0
+	}
+
+	return ttl + c.staleIfError
+}
+
+func (c *Cache[K, V]) refresh(ctx context.Context, id K) {
+	c.background(ctx, refreshQueuePrefix+c.toKey(id), func(ctx context.Context) error {
+		_, err := c.fetch(ctx, id)
+
+		return err
+	})
+}
+
+func (c *Cache[K, V]) refreshMany(ctx context.Context, ids []K) {
+	if len(ids) == 0 {
+		return
+	}
+
+	c.background(ctx, "", func(ctx context.Context) error {
+		return c.warm(ctx, ids)
+	})
+}
+
+func (c *Cache[K, V]) fetchOrStale(ctx context.Context, id K, staleValue V, writtenAt time.Time) (V, error) {
+	value, err := c.fetch(ctx, id)
+	if err == nil || errors.Is(err, model.ErrNotFound) || !c.servableOnError(writtenAt, c.entryTTL(staleValue)) {
+		return value, err
+	}
+
+	slog.LogAttrs(ctx, slog.LevelWarn, "serving stale value", slog.String("key", c.toKey(id)), slog.Any("error", err))
+
+	return staleValue, nil
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEnvelope(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	item, ok := unwrapEnvelope(wrapEnvelope(2, now, []byte("hello")))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("hello"), item.payload)
+	assert.True(t, now.Equal(item.writtenAt))
+	assert.Equal(t, uint32(2), item.version)
+	assert.Equal(t, byte(0), item.flags)
+
+	// A raw value starting like the previous envelope marker.
+	_, ok = unwrapEnvelope([]byte("\xffhello world, long enough"))
+	assert.False(t, ok)
+}
+
+func TestGetSoftTTL(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		onMissErr error
+		age       time.Duration
+		want      string
+		wantErr   error
+		wantMiss  int32
+	}{
+		"fresh": {
+			age:  time.Second,
+			want: "cached",
+		},
+		"stale": {
+			age:      time.Minute * 2,
+			want:     "cached",
+			wantMiss: 1,
+		},
+		"expired": {
+			age:      time.Minute * 11,
+			want:     "fetched",
+			wantMiss: 1,
+		},
+		"stale if error": {
+			onMissErr: errors.New("fetch failed"),
+			age:       time.Minute * 11,
+			want:      "cached",
+			wantMiss:  1,
+		},
+		"not found": {
+			onMissErr: model.ErrNotFound,
+			age:       time.Minute * 11,
+			wantErr:   model.ErrNotFound,
+			wantMiss:  1,
+		},
+		"error after window": {
+			onMissErr: errors.New("fetch failed"),
+			age:       time.Minute * 21,
+			wantErr:   errors.New("fetch failed"),
+			wantMiss:  1,
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			var calls atomic.Int32
+			missed := make(chan struct{}, 1)
+
+			mockRedisClient := mocks.NewRedisClient(ctrl)
+			mockRedisClient.EXPECT().Enabled().Return(true)
+			mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(wrapEnvelope(0, time.Now().Add(-testCase.age), []byte("cached")), nil)
+			mockRedisClient.EXPECT().Store(gomock.Any(), "8000", gomock.Any(), time.Minute*20).Return(nil).AnyTimes()
+
+			instance := New("test", mockRedisClient, strconv.Itoa, func(_ context.Context, _ int) (string, error) {
+				defer func() { missed <- struct{}{} }()
+
+				calls.Add(1)
+
+				if testCase.onMissErr != nil {
+					return "", testCase.onMissErr
+				}
+
+				return "fetched", nil
+			}, nil, nil).
+				WithSerializer(StringSerializer{}).
+				WithTTL(time.Minute*10).
+				WithSoftTTL(time.Minute, time.Minute*10)
+
+			got, gotErr := instance.Get(context.Background(), 8000)
+
+			assert.Equal(t, testCase.want, got)
+
+			if testCase.wantErr == nil {
+				assert.NoError(t, gotErr)
+			} else {
+				assert.ErrorContains(t, gotErr, testCase.wantErr.Error())
+			}
+
+			if testCase.wantMiss != 0 {
+				select {
+				case <-missed:
+				case <-time.After(time.Second):
+				}
+			}
+
+			assert.Equal(t, testCase.wantMiss, calls.Load())
+		})
+	}
+}
+
+func TestWaitForValueStale(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(wrapEnvelope(0, time.Now().Add(-time.Hour), []byte("stale")), nil)
+	mockRedisClient.EXPECT().Load(gomock.Any(), "8000").Return(wrapEnvelope(0, time.Now(), []byte("fresh")), nil)
+
+	instance := New[int, string]("test", mockRedisClient, strconv.Itoa, nil, nil, nil).
+		WithSerializer(StringSerializer{}).
+		WithTTL(time.Minute).
+		WithSoftTTL(time.Second*30, time.Hour).
+		WithDistributedLock(time.Second, time.Second)
+
+	got, ok, err := instance.waitForValue(context.Background(), 8000, "8000")
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "fresh", got)
+}
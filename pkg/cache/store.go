@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/ViBiOh/httputils/v4/pkg/telemetry"
 	"go.opentelemetry.io/otel/trace"
@@ -18,9 +19,16 @@ func (c *Cache[K, V]) Store(ctx context.Context, id K, value V) error {
 }
 
 func (c *Cache[K, V]) store(ctx context.Context, id K, value V) error {
-	c.memoryWrite(id, value, c.ttl)
+	ttl := c.entryTTL(value)
+	if ttl < 0 {
+		return nil
+	}
+
+	c.memoryWrite(id, value, c.freshTTL(time.Time{}, ttl))
+
+	if err := c.redisWrite(ctx, id, value, ttl); err != nil {
+		c.metrics.recordStoreError(ctx)
 
-	if err := c.redisWrite(ctx, id, value); err != nil {
 		return err
 	}
 
@@ -41,7 +49,9 @@ func (c *Cache[K, V]) storeMany(ctx context.Context, values []V, indexedIDs Inde
 	ctx, end := telemetry.StartSpan(ctx, c.tracer, "store_many", trace.WithSpanKind(trace.SpanKindInternal))
 	defer end(&err)
 
-	toSet := make(map[string]any)
+	toSet := make(map[time.Duration]map[string]any)
+	tagged := make(map[string][]string)
+	now := time.Now()
 
 	for _, indexed := range indexedIDs {
 		id := indexed.id
@@ -60,21 +70,44 @@ func (c *Cache[K, V]) storeMany(ctx context.Context, values []V, indexedIDs Inde
 			continue
 		}
 
-		c.memoryWrite(id, values[index], c.ttl)
+		ttl := c.entryTTL(values[index])
+		if ttl < 0 {
+			continue
+		}
+
+		c.memoryWrite(id, values[index], c.freshTTL(time.Time{}, ttl))
 
 		if c.write != nil {
-			toSet[key] = payload
+			if c.withEnvelope() {
+				payload = wrapEnvelope(c.version, now, payload)
+			}
+
+			storageTTL := c.storageTTL(ttl)
+			if toSet[storageTTL] == nil {
+				toSet[storageTTL] = make(map[string]any)
+			}
+
+			toSet[storageTTL][key] = payload
+			c.addTags(tagged, key, id, values[index])
 		}
 	}
 
-	if c.write != nil {
-		return c.write.StoreMany(ctx, toSet, c.ttl)
+	if c.write == nil {
+		return nil
 	}
 
-	return nil
+	for ttl, payloads := range toSet {
+		if err = c.write.StoreMany(ctx, payloads, ttl); err != nil {
+			c.metrics.recordStoreError(ctx)
+
+			return err
+		}
+	}
+
+	return c.redisTag(ctx, tagged, longestTTL(toSet))
 }
 
-func (c *Cache[K, V]) redisWrite(ctx context.Context, id K, value V) (err error) {
+func (c *Cache[K, V]) redisWrite(ctx context.Context, id K, value V, ttl time.Duration) (err error) {
 	if c.write == nil {
 		return nil
 	}
@@ -82,14 +115,19 @@ func (c *Cache[K, V]) redisWrite(ctx context.Context, id K, value V) (err error)
 	ctx, end := telemetry.StartSpan(ctx, c.tracer, "store", trace.WithSpanKind(trace.SpanKindInternal))
 	defer end(&err)
 
-	payload, err := c.serializer.Encode(value)
+	payload, err := c.encode(value)
 	if err != nil {
 		return fmt.Errorf("encoding: %w", err)
 	}
 
-	if err = c.write.Store(ctx, c.toKey(id), payload, c.ttl); err != nil {
+	key := c.toKey(id)
+
+	if err = c.write.Store(ctx, key, payload, c.storageTTL(ttl)); err != nil {
 		return fmt.Errorf("store: %w", err)
 	}
 
-	return nil
+	tagged := make(map[string][]string)
+	c.addTags(tagged, key, id, value)
+
+	return c.redisTag(ctx, tagged, c.storageTTL(ttl))
 }
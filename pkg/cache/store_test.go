@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func noFetchString(_ context.Context, _ int) (string, error) {
+	return "", nil
+}
+
+func TestStoreTTLFunc(t *testing.T) {
+	t.Parallel()
+
+	ttlFunc := func(value string) time.Duration {
+		switch value {
+		case "short":
+			return time.Second
+		case "expired":
+			return -time.Second
+		default:
+			return 0
+		}
+	}
+
+	cases := map[string]struct {
+		value   string
+		wantTTL time.Duration
+	}{
+		"entry": {
+			value:   "short",
+			wantTTL: time.Second,
+		},
+		"default": {
+			value:   "long",
+			wantTTL: time.Minute,
+		},
+		"expired": {
+			value: "expired",
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRedisClient := mocks.NewRedisClient(ctrl)
+			mockRedisClient.EXPECT().Enabled().Return(true)
+
+			if testCase.wantTTL != 0 {
+				mockRedisClient.EXPECT().Store(gomock.Any(), "8000", []byte(testCase.value), testCase.wantTTL).Return(nil)
+			}
+
+			instance := New("test", mockRedisClient, strconv.Itoa, noFetchString, nil, nil).
+				WithSerializer(StringSerializer{}).
+				WithTTL(time.Minute).
+				WithTTLFunc(ttlFunc)
+
+			assert.NoError(t, instance.Store(context.Background(), 8000, testCase.value))
+		})
+	}
+}
+
+func TestStoreManyTTLFunc(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().StoreMany(gomock.Any(), map[string]any{"1": []byte("1s")}, time.Second).Return(nil)
+	mockRedisClient.EXPECT().StoreMany(gomock.Any(), map[string]any{"2": []byte("default"), "4": []byte("default")}, time.Minute).Return(nil)
+
+	instance := New("test", mockRedisClient, strconv.Itoa, noFetchString, nil, nil).
+		WithSerializer(StringSerializer{}).
+		WithTTL(time.Minute).
+		WithTTLFunc(func(value string) time.Duration {
+			if value == "default" {
+				return 0
+			}
+
+			duration, _ := time.ParseDuration(value)
+
+			return duration
+		})
+
+	assert.NoError(t, instance.storeMany(context.Background(), []string{"1s", "default", "-1s", "default"}, IndexedIDs[int]{
+		{id: 1, index: 0},
+		{id: 2, index: 1},
+		{id: 3, index: 2},
+		{id: 4, index: 3},
+	}))
+}
+
+func TestFreshTTL(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		softTTL time.Duration
+		ttl     time.Duration
+		want    time.Duration
+	}{
+		"ttl": {
+			ttl:  time.Minute,
+			want: time.Minute,
+		},
+		"soft": {
+			softTTL: time.Second,
+			ttl:     time.Minute,
+			want:    time.Second,
+		},
+		"shorter entry": {
+			softTTL: time.Minute,
+			ttl:     time.Second,
+			want:    time.Second,
+		},
+		"no expiration": {
+			softTTL: time.Second,
+			want:    time.Second,
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			instance := New("test", nil, strconv.Itoa, noFetchString, nil, nil).WithSoftTTL(testCase.softTTL, 0)
+
+			assert.Equal(t, testCase.want, instance.freshTTL(time.Time{}, testCase.ttl))
+		})
+	}
+}
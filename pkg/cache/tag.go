@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+	"github.com/ViBiOh/httputils/v4/pkg/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tagKey namespaces the tag with the cache name, so caches sharing a Redis don't evict each other's entries.
+func (c *Cache[K, V]) tagKey(tag string) string {
+	return "tag:" + c.name + ":" + tag
+}
+
+func tagChannel(channel string) string {
+	return channel + ":tags"
+}
+
+// WithTags indexes the entries by the tags returned by the callback, for EvictTag. It has to be called before WithClientSideCaching, so the memory cache listens to the tag evictions.
+func (c *Cache[K, V]) WithTags(cb tagger[K, V]) *Cache[K, V] {
+	c.tagger = cb
+
+	return c
+}
+
+func (c *Cache[K, V]) addTags(tagged map[string][]string, key string, id K, value V) {
+	if c.tagger == nil {
+		return
+	}
+
+	for _, tag := range c.tagger(id, value) {
+		tagged[c.tagKey(tag)] = append(tagged[c.tagKey(tag)], key)
+	}
+}
+
+// redisTag keeps the tag sets for the given storage TTL, it should be the longest TTL of the tagged keys.
+func (c *Cache[K, V]) redisTag(ctx context.Context, tagged map[string][]string, ttl time.Duration) error {
+	if c.write == nil || len(tagged) == 0 {
+		return nil
+	}
+
+	if err := c.write.Tag(ctx, tagged, ttl); err != nil {
+		return fmt.Errorf("tag: %w", err)
+	}
+
+	return nil
+}
+
+// longestTTL returns zero, meaning no expiration, when one of the TTLs is zero.
+func longestTTL[T any](values map[time.Duration]T) time.Duration {
+	var output time.Duration
+
+	for ttl := range values {
+		if ttl == 0 {
+			return 0
+		}
+
+		output = max(output, ttl)
+	}
+
+	return output
+}
+
+// EvictTag removes every entry tagged with the given tag, from Redis and from the client-side caches.
+func (c *Cache[K, V]) EvictTag(ctx context.Context, tag string) error {
+	if err := c.redisEvictTag(ctx, tag); err != nil {
+		return err
+	}
+
+	if err := c.memoryEvictTag(ctx, tag); err != nil {
+		return err
+	}
+
+	c.metrics.recordEviction(ctx)
+
+	return nil
+}
+
+func (c *Cache[K, V]) redisEvictTag(ctx context.Context, tag string) (err error) {
+	if c.write == nil {
+		return nil
+	}
+
+	ctx, end := telemetry.StartSpan(ctx, c.tracer, "evict_tag", trace.WithSpanKind(trace.SpanKindInternal))
+	defer end(&err)
+
+	slog.LogAttrs(ctx, slog.LevelDebug, "evicting tag from redis cache", slog.String("tag", tag))
+
+	if err = c.write.DeleteTag(ctx, c.tagKey(tag)); err != nil {
+		return fmt.Errorf("evict tag `%s` from cache: %w", tag, err)
+	}
+
+	return nil
+}
+
+func (c *Cache[K, V]) memoryEvictTag(ctx context.Context, tag string) (err error) {
+	if c.memory == nil {
+		return nil
+	}
+
+	c.memoryDeleteTag(tag)
+
+	if c.write == nil {
+		return nil
+	}
+
+	ctx, end := telemetry.StartSpan(ctx, c.tracer, "evict_tag notify", trace.WithSpanKind(trace.SpanKindInternal))
+	defer end(&err)
+
+	if err := c.write.PublishJSON(ctx, tagChannel(c.channel), tag); err != nil && !errors.Is(err, redis.ErrNoSubscriber) {
+		return fmt.Errorf("evict tag notify for `%s`: %w", tag, err)
+	}
+
+	return nil
+}
+
+func (c *Cache[K, V]) memoryDeleteTag(tag string) {
+	if c.tagger == nil {
+		return
+	}
+
+	c.memory.DeleteFunc(func(id K, value V) bool {
+		return slices.Contains(c.tagger(id, value), tag)
+	})
+}
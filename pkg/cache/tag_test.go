@@ -0,0 +1,128 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/cache"
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStoreTags(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().Store(gomock.Any(), "8000", gomock.Any(), time.Minute).Return(nil)
+	mockRedisClient.EXPECT().Tag(gomock.Any(), map[string][]string{"tag:test:user:1": {"8000"}, "tag:test:all": {"8000"}}, time.Minute).Return(nil)
+
+	instance := cache.New("test", mockRedisClient, strconv.Itoa, noFetch, nil, nil).
+		WithTTL(time.Minute).
+		WithTags(func(_ int, value Repository) []string {
+			return []string{"user:" + strconv.Itoa(value.Owner.ID), "all"}
+		})
+
+	value := Repository{ID: 8000}
+	value.Owner.ID = 1
+
+	assert.NoError(t, instance.Store(context.Background(), 8000, value))
+}
+
+func TestEvictTag(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		deleteErr error
+		wantErr   error
+	}{
+		"evict": {
+			nil,
+			nil,
+		},
+		"evict error": {
+			errors.New("redis failed"),
+			errors.New("evict tag `user:1` from cache"),
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRedisClient := mocks.NewRedisClient(ctrl)
+			mockRedisClient.EXPECT().Enabled().Return(true)
+			mockRedisClient.EXPECT().DeleteTag(gomock.Any(), "tag:test:user:1").Return(testCase.deleteErr)
+
+			instance := cache.New("test", mockRedisClient, strconv.Itoa, noFetch, nil, nil)
+
+			gotErr := instance.EvictTag(context.Background(), "user:1")
+			if testCase.wantErr == nil {
+				assert.NoError(t, gotErr)
+			} else {
+				assert.ErrorContains(t, gotErr, testCase.wantErr.Error())
+			}
+		})
+	}
+}
+
+func TestTagSubscription(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		tagger   func(int, Repository) []string
+		channels []string
+	}{
+		"untagged": {
+			nil,
+			[]string{"test"},
+		},
+		"tagged": {
+			func(int, Repository) []string { return []string{"all"} },
+			[]string{"test", "test:tags"},
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			subscribed := make(chan string, 2)
+
+			mockRedisClient := mocks.NewRedisClient(ctrl)
+			mockRedisClient.EXPECT().Enabled().Return(true)
+			mockRedisClient.EXPECT().Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, channel string, _ ...any) {
+				subscribed <- channel
+			}).Return(nil, func(context.Context) {}).Times(len(testCase.channels))
+
+			instance := cache.New("test", mockRedisClient, strconv.Itoa, noFetch, nil, nil)
+			if testCase.tagger != nil {
+				instance.WithTags(testCase.tagger)
+			}
+
+			instance.WithClientSideCaching(t.Context(), "test", 10)
+
+			var channels []string
+			for range testCase.channels {
+				select {
+				case channel := <-subscribed:
+					channels = append(channels, channel)
+				case <-time.After(time.Second):
+					t.Fatal("not subscribed")
+				}
+			}
+
+			assert.ElementsMatch(t, testCase.channels, channels)
+		})
+	}
+}
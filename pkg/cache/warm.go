@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ViBiOh/httputils/v4/pkg/recoverer"
+	"github.com/ViBiOh/httputils/v4/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var warmBatchSize = 100
+
+// Warm fetches the given ids by batch and stores them, e.g. after a Redis flush or a cold start. Ids that fail to be fetched are logged and skipped, the whole batch with WithMissMany.
+func (c *Cache[K, V]) Warm(ctx context.Context, ids ...K) (err error) {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx, end := telemetry.StartSpan(ctx, c.tracer, "warm", trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attribute.Int("count", len(ids))))
+	defer end(&err)
+
+	for start := 0; start < len(ids); start += warmBatchSize {
+		if err = c.warm(ctx, ids[start:min(start+warmBatchSize, len(ids))]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WarmScan refreshes every key matching the pattern. The fromKey callback converts a key back to its id, keys it fails to convert are ignored.
+func (c *Cache[K, V]) WarmScan(ctx context.Context, pattern string, fromKey func(string) (K, error)) (err error) {
+	if c.read == nil {
+		return nil
+	}
+
+	ctx, end := telemetry.StartSpan(ctx, c.tracer, "warm_scan", trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attribute.String("pattern", pattern)))
+	defer end(&err)
+
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	scanOutput := make(chan string, warmBatchSize)
+	done := make(chan struct{})
+
+	var warmErr error
+
+	go func() {
+		defer close(done)
+
+		// On a panic, the scan is stopped and its remaining keys discarded so it never blocks.
+		defer func() {
+			for range scanOutput {
+			}
+		}()
+		defer cancel()
+		defer recoverer.Error(&warmErr)
+
+		ids := make([]K, 0, warmBatchSize)
+
+		for key := range scanOutput {
+			id, err := fromKey(key)
+			if err != nil {
+				slog.LogAttrs(ctx, slog.LevelDebug, "ignoring key while warming", slog.String("key", key), slog.Any("error", err))
+
+				continue
+			}
+
+			if ids = append(ids, id); len(ids) == warmBatchSize {
+				warmErr = errors.Join(warmErr, c.warm(ctx, ids))
+				ids = make([]K, 0, warmBatchSize)
+			}
+		}
+
+		if len(ids) != 0 {
+			warmErr = errors.Join(warmErr, c.warm(ctx, ids))
+		}
+	}()
+
+	if err = c.read.Scan(scanCtx, pattern, scanOutput, int64(warmBatchSize)); err != nil {
+		err = fmt.Errorf("scan: %w", err)
+	}
+
+	<-done
+
+	return errors.Join(err, warmErr)
+}
+
+func (c *Cache[K, V]) warm(ctx context.Context, ids []K) error {
+	values, err := c.fetchAll(skipStore(ctx), nil, ids)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("fetch: %w", err)
+		}
+
+		slog.LogAttrs(ctx, slog.LevelError, "warm batch", slog.Int("count", len(ids)), slog.Any("error", err))
+
+		return nil
+	}
+
+	indexedIDs := make(IndexedIDs[K], len(ids))
+	for index, id := range ids {
+		indexedIDs[index] = IndexedID[K]{id: id, index: index}
+	}
+
+	if err = c.storeMany(ctx, values, indexedIDs); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+
+	return nil
+}
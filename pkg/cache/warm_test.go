@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWarm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().StoreMany(gomock.Any(), map[string]any{"1": []byte("1"), "2": []byte("2")}, time.Minute).Return(nil)
+	mockRedisClient.EXPECT().StoreMany(gomock.Any(), map[string]any{"5": []byte("5")}, time.Minute).Return(nil)
+
+	instance := New("test", mockRedisClient, strconv.Itoa, noFetchString, nil, nil).
+		WithSerializer(StringSerializer{}).
+		WithTTL(time.Minute).
+		WithMissMany(func(_ context.Context, ids []int) ([]string, error) {
+			if slices.Contains(ids, 3) {
+				return nil, errors.New("batch failed")
+			}
+
+			output := make([]string, len(ids))
+			for index, id := range ids {
+				output[index] = strconv.Itoa(id)
+			}
+
+			return output, nil
+		})
+
+	previousBatchSize := warmBatchSize
+	warmBatchSize = 2
+	t.Cleanup(func() { warmBatchSize = previousBatchSize })
+
+	assert.NoError(t, instance.Warm(context.Background(), 1, 2, 3, 4, 5))
+}
+
+func TestWarmScan(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().Scan(gomock.Any(), "repo:*", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, output chan<- string, _ int64) error {
+		defer close(output)
+
+		for _, key := range []string{"repo:1", "repo:1:lock", "repo:2"} {
+			output <- key
+		}
+
+		return nil
+	})
+	mockRedisClient.EXPECT().StoreMany(gomock.Any(), map[string]any{"repo:1": []byte("fetched 1"), "repo:2": []byte("fetched 2")}, gomock.Any()).Return(nil)
+
+	instance := New("test", mockRedisClient, func(id int) string { return "repo:" + strconv.Itoa(id) }, func(_ context.Context, id int) (string, error) {
+		return "fetched " + strconv.Itoa(id), nil
+	}, nil, nil).WithSerializer(StringSerializer{})
+
+	assert.NoError(t, instance.WarmScan(context.Background(), "repo:*", func(key string) (int, error) {
+		return strconv.Atoi(strings.TrimPrefix(key, "repo:"))
+	}))
+}
+
+func TestWarmScanPanic(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRedisClient := mocks.NewRedisClient(ctrl)
+	mockRedisClient.EXPECT().Enabled().Return(true)
+	mockRedisClient.EXPECT().Scan(gomock.Any(), "repo:*", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, output chan<- string, _ int64) error {
+		defer close(output)
+
+		for id := range warmBatchSize * 3 {
+			output <- "repo:" + strconv.Itoa(id)
+		}
+
+		return nil
+	})
+
+	instance := New("test", mockRedisClient, func(id int) string { return "repo:" + strconv.Itoa(id) }, noFetchString, nil, nil).WithSerializer(StringSerializer{})
+
+	err := instance.WarmScan(context.Background(), "repo:*", func(string) (int, error) {
+		panic("boom")
+	})
+
+	assert.ErrorContains(t, err, "recovered from panic: boom")
+}
@@ -0,0 +1,170 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/recoverer"
+)
+
+type call[V any] struct {
+	err   error
+	value V
+	done  chan struct{}
+}
+
+type SingleFlight[K comparable, V any] struct {
+	calls   map[K]*call[V]
+	timeout time.Duration
+	mutex   sync.Mutex
+}
+
+// NewSingleFlight creates a SingleFlight whose calls run during at most the timeout, zero not bounding them.
+func NewSingleFlight[K comparable, V any](timeout time.Duration) *SingleFlight[K, V] {
+	return &SingleFlight[K, V]{
+		calls:   make(map[K]*call[V]),
+		timeout: timeout,
+	}
+}
+
+// Do executes fn once for all concurrent callers of the same key. The boolean is true when the result was shared from another caller.
+//
+// fn runs with the values of the context of the first caller, but not its cancellation: a caller going away doesn't fail the others. Each caller stops waiting when its own context is done.
+func (sf *SingleFlight[K, V]) Do(ctx context.Context, key K, fn func(context.Context) (V, error)) (V, bool, error) {
+	sf.mutex.Lock()
+
+	current, shared := sf.calls[key]
+	if !shared {
+		current = sf.register(key)
+	}
+
+	sf.mutex.Unlock()
+
+	if !shared {
+		go sf.execute(ctx, key, current, fn)
+	}
+
+	select {
+	case <-current.done:
+		return current.value, shared, current.err
+	case <-ctx.Done():
+		var zero V
+
+		return zero, shared, context.Cause(ctx)
+	}
+}
+
+// DoMany executes fn with the indexes of the keys that are not already in-flight, and waits for the others. It returns values in the same order as the keys and the number of keys that were shared from another caller. fn runs like in Do.
+func (sf *SingleFlight[K, V]) DoMany(ctx context.Context, keys []K, fn func(context.Context, []int) ([]V, error)) ([]V, int, error) {
+	calls := make([]*call[V], len(keys))
+	owned := make(map[K]*call[V])
+
+	var indexes []int
+	var coalesced int
+
+	sf.mutex.Lock()
+
+	for index, key := range keys {
+		if current, ok := owned[key]; ok {
+			calls[index] = current
+		} else if current, ok := sf.calls[key]; ok {
+			calls[index] = current
+			coalesced++
+		} else {
+			calls[index] = sf.register(key)
+			owned[key] = calls[index]
+			indexes = append(indexes, index)
+		}
+	}
+
+	sf.mutex.Unlock()
+
+	if len(indexes) != 0 {
+		go sf.executeMany(ctx, keys, indexes, owned, fn)
+	}
+
+	output := make([]V, len(keys))
+
+	var err error
+
+	for index, current := range calls {
+		select {
+		case <-current.done:
+		case <-ctx.Done():
+			return nil, coalesced, context.Cause(ctx)
+		}
+
+		output[index] = current.value
+
+		if current.err != nil && !errors.Is(err, current.err) {
+			err = errors.Join(err, current.err)
+		}
+	}
+
+	return output, coalesced, err
+}
+
+// detach returns a context with the values of the given one, cancelled only by the timeout.
+func (sf *SingleFlight[K, V]) detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+
+	if sf.timeout > 0 {
+		return context.WithTimeout(ctx, sf.timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+func (sf *SingleFlight[K, V]) execute(ctx context.Context, key K, current *call[V], fn func(context.Context) (V, error)) {
+	defer sf.done(key, current)
+	defer recoverer.Error(&current.err)
+
+	ctx, cancel := sf.detach(ctx)
+	defer cancel()
+
+	current.value, current.err = fn(ctx)
+}
+
+func (sf *SingleFlight[K, V]) executeMany(ctx context.Context, keys []K, indexes []int, owned map[K]*call[V], fn func(context.Context, []int) ([]V, error)) {
+	var values []V
+	var err error
+
+	defer func() {
+		for position, index := range indexes {
+			current := owned[keys[index]]
+
+			if position < len(values) {
+				current.value = values[position]
+			}
+
+			current.err = err
+
+			sf.done(keys[index], current)
+		}
+	}()
+
+	defer recoverer.Error(&err)
+
+	ctx, cancel := sf.detach(ctx)
+	defer cancel()
+
+	values, err = fn(ctx, indexes)
+}
+
+func (sf *SingleFlight[K, V]) register(key K) *call[V] {
+	current := &call[V]{done: make(chan struct{})}
+
+	sf.calls[key] = current
+
+	return current
+}
+
+func (sf *SingleFlight[K, V]) done(key K, current *call[V]) {
+	sf.mutex.Lock()
+	delete(sf.calls, key)
+	sf.mutex.Unlock()
+
+	close(current.done)
+}
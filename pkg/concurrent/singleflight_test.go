@@ -0,0 +1,261 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleFlightDo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("coalesce", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		var calls, shared atomic.Int32
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+			value, isShared, err := instance.Do(context.Background(), "hello", func(context.Context) (int, error) {
+				calls.Add(1)
+				close(started)
+				<-release
+
+				return 42, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 42, value)
+			assert.False(t, isShared)
+		})
+
+		<-started
+
+		for range 5 {
+			wg.Go(func() {
+				value, isShared, err := instance.Do(context.Background(), "hello", func(context.Context) (int, error) {
+					calls.Add(1)
+
+					return 0, nil
+				})
+
+				assert.NoError(t, err)
+				assert.Equal(t, 42, value)
+
+				if isShared {
+					shared.Add(1)
+				}
+			})
+		}
+
+		// Wait for callers to join the in-flight call
+		time.Sleep(time.Millisecond * 50)
+
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, int32(5), shared.Load())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		_, isShared, err := instance.Do(context.Background(), "hello", func(context.Context) (int, error) {
+			return 0, errors.New("failed")
+		})
+
+		assert.ErrorContains(t, err, "failed")
+		assert.False(t, isShared)
+		assert.Empty(t, instance.calls)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		_, _, err := instance.Do(context.Background(), "hello", func(context.Context) (int, error) {
+			panic("boom")
+		})
+
+		assert.ErrorContains(t, err, "recovered from panic: boom")
+		assert.Empty(t, instance.calls)
+	})
+
+	t.Run("caller cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+			_, _, err := instance.Do(ctx, "hello", func(ctx context.Context) (int, error) {
+				close(started)
+				<-release
+
+				return 42, ctx.Err()
+			})
+
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+
+		<-started
+
+		wg.Go(func() {
+			value, isShared, err := instance.Do(context.Background(), "hello", func(context.Context) (int, error) {
+				return 0, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 42, value)
+			assert.True(t, isShared)
+		})
+
+		cancel()
+
+		// Wait for the caller to join the in-flight call
+		time.Sleep(time.Millisecond * 50)
+
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](time.Millisecond * 10)
+
+		_, _, err := instance.Do(context.Background(), "hello", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestSingleFlightDoMany(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all owned", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		got, coalesced, err := instance.DoMany(context.Background(), []string{"1", "2", "1"}, func(_ context.Context, indexes []int) ([]int, error) {
+			assert.Equal(t, []int{0, 1}, indexes)
+
+			return []int{1, 2}, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 1}, got)
+		assert.Equal(t, 0, coalesced)
+		assert.Empty(t, instance.calls)
+	})
+
+	t.Run("shared with do", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+			_, _, _ = instance.Do(context.Background(), "2", func(context.Context) (int, error) {
+				close(started)
+				<-release
+
+				return 2, nil
+			})
+		})
+
+		<-started
+
+		var got []int
+		var coalesced int
+		var err error
+
+		wg.Go(func() {
+			got, coalesced, err = instance.DoMany(context.Background(), []string{"1", "2", "3"}, func(_ context.Context, indexes []int) ([]int, error) {
+				assert.Equal(t, []int{0, 2}, indexes)
+
+				close(release)
+
+				return []int{1, 3}, nil
+			})
+		})
+
+		wg.Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, got)
+		assert.Equal(t, 1, coalesced)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		got, _, err := instance.DoMany(context.Background(), []string{"1", "2"}, func(context.Context, []int) ([]int, error) {
+			return nil, errors.New("failed")
+		})
+
+		assert.ErrorContains(t, err, "failed")
+		assert.Equal(t, []int{0, 0}, got)
+		assert.Empty(t, instance.calls)
+	})
+
+	t.Run("caller cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		instance := NewSingleFlight[string, int](0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		release := make(chan struct{})
+
+		_, _, err := instance.DoMany(ctx, []string{"1"}, func(context.Context, []int) ([]int, error) {
+			<-release
+
+			return []int{1}, nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+
+		got, coalesced, err := instance.DoMany(context.Background(), []string{"1"}, func(context.Context, []int) ([]int, error) {
+			return []int{1}, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, got)
+		assert.LessOrEqual(t, coalesced, 1)
+	})
+}
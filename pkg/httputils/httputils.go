@@ -10,13 +10,25 @@ import (
 	"github.com/ViBiOh/httputils/v4/pkg/model"
 )
 
+const cachesPath = "/caches"
+
 func Handler(handler http.Handler, healthService *health.Service, middlewares ...model.Middleware) http.Handler {
+	return HandlerWithCaches(handler, healthService, nil, middlewares...)
+}
+
+// HandlerWithCaches mounts the caches handler, e.g. from cache.Registry, under /caches/ when not nil.
+func HandlerWithCaches(handler http.Handler, healthService *health.Service, caches http.Handler, middlewares ...model.Middleware) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.Handle(fmt.Sprintf("GET %s", health.LivePath), healthService.HealthHandler())
 	mux.Handle(fmt.Sprintf("GET %s", health.ReadyPath), healthService.ReadyHandler())
 
 	mux.Handle("GET /version", versionHandler())
+
+	if caches != nil {
+		mux.Handle(cachesPath+"/", http.StripPrefix(cachesPath, caches))
+	}
+
 	mux.Handle("/", model.ChainMiddlewares(handler, append([]model.Middleware{httprecover.Middleware}, middlewares...)...))
 
 	return mux
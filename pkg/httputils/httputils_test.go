@@ -20,6 +20,12 @@ func TestHandler(t *testing.T) {
 		}
 	})
 
+	caches := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(r.URL.Path)); err != nil {
+			t.Error(err)
+		}
+	})
+
 	t.Setenv("VERSION", "httputils/TestHandler")
 
 	cases := map[string]struct {
@@ -47,6 +53,11 @@ func TestHandler(t *testing.T) {
 			"",
 			http.StatusNoContent,
 		},
+		"caches": {
+			httptest.NewRequest(http.MethodGet, "/caches/hello/keys/1", nil),
+			"/hello/keys/1",
+			http.StatusOK,
+		},
 	}
 
 	for intention, testCase := range cases {
@@ -54,7 +65,7 @@ func TestHandler(t *testing.T) {
 			t.Parallel()
 
 			writer := httptest.NewRecorder()
-			Handler(handler, healthService).ServeHTTP(writer, testCase.request)
+			HandlerWithCaches(handler, healthService, caches).ServeHTTP(writer, testCase.request)
 
 			if got := writer.Code; got != testCase.wantStatus {
 				t.Errorf("Handler = %d, want %d", got, testCase.wantStatus)
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	redis0 "github.com/ViBiOh/httputils/v4/pkg/redis"
 	redis "github.com/redis/go-redis/v9"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -61,6 +62,20 @@ func (mr *RedisClientMockRecorder) Delete(ctx any, keys ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*RedisClient)(nil).Delete), varargs...)
 }
 
+// DeleteTag mocks base method.
+func (m *RedisClient) DeleteTag(ctx context.Context, tag string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *RedisClientMockRecorder) DeleteTag(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*RedisClient)(nil).DeleteTag), ctx, tag)
+}
+
 // Enabled mocks base method.
 func (m *RedisClient) Enabled() bool {
 	m.ctrl.T.Helper()
@@ -75,6 +90,21 @@ func (mr *RedisClientMockRecorder) Enabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*RedisClient)(nil).Enabled))
 }
 
+// Exclusive mocks base method.
+func (m *RedisClient) Exclusive(ctx context.Context, name string, timeout time.Duration, action func(context.Context) error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exclusive", ctx, name, timeout, action)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exclusive indicates an expected call of Exclusive.
+func (mr *RedisClientMockRecorder) Exclusive(ctx, name, timeout, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exclusive", reflect.TypeOf((*RedisClient)(nil).Exclusive), ctx, name, timeout, action)
+}
+
 // Expire mocks base method.
 func (m *RedisClient) Expire(ctx context.Context, ttl time.Duration, keys ...string) error {
 	m.ctrl.T.Helper()
@@ -157,6 +187,20 @@ func (mr *RedisClientMockRecorder) PublishJSON(ctx, channel, value any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishJSON", reflect.TypeOf((*RedisClient)(nil).PublishJSON), ctx, channel, value)
 }
 
+// Scan mocks base method.
+func (m *RedisClient) Scan(ctx context.Context, pattern string, output chan<- string, pageSize int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, pattern, output, pageSize)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *RedisClientMockRecorder) Scan(ctx, pattern, output, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*RedisClient)(nil).Scan), ctx, pattern, output, pageSize)
+}
+
 // Store mocks base method.
 func (m *RedisClient) Store(ctx context.Context, key string, value any, ttl time.Duration) error {
 	m.ctrl.T.Helper()
@@ -186,16 +230,51 @@ func (mr *RedisClientMockRecorder) StoreMany(ctx, values, ttl any) *gomock.Call
 }
 
 // Subscribe mocks base method.
-func (m *RedisClient) Subscribe(ctx context.Context, channel string) (<-chan *redis.Message, func(context.Context)) {
+func (m *RedisClient) Subscribe(ctx context.Context, channel string, options ...redis0.SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Subscribe", ctx, channel)
+	varargs := []any{ctx, channel}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Subscribe", varargs...)
 	ret0, _ := ret[0].(<-chan *redis.Message)
 	ret1, _ := ret[1].(func(context.Context))
 	return ret0, ret1
 }
 
 // Subscribe indicates an expected call of Subscribe.
-func (mr *RedisClientMockRecorder) Subscribe(ctx, channel any) *gomock.Call {
+func (mr *RedisClientMockRecorder) Subscribe(ctx, channel any, options ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, channel}, options...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*RedisClient)(nil).Subscribe), varargs...)
+}
+
+// TTL mocks base method.
+func (m *RedisClient) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *RedisClientMockRecorder) TTL(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*RedisClient)(nil).TTL), ctx, key)
+}
+
+// Tag mocks base method.
+func (m *RedisClient) Tag(ctx context.Context, tagged map[string][]string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tag", ctx, tagged, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tag indicates an expected call of Tag.
+func (mr *RedisClientMockRecorder) Tag(ctx, tagged, ttl any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*RedisClient)(nil).Subscribe), ctx, channel)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tag", reflect.TypeOf((*RedisClient)(nil).Tag), ctx, tagged, ttl)
 }
@@ -0,0 +1,94 @@
+package outbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// encodeHeaders stores the headers as JSON. Types that JSON doesn't round-trip, like []byte, time.Time or amqp.Decimal, are rejected.
+func encodeHeaders(headers amqp.Table) ([]byte, error) {
+	if err := checkHeader(headers); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(headers)
+}
+
+func checkHeader(value any) error {
+	switch typed := value.(type) {
+	case nil, bool, string, int, int8, int16, int32, int64, uint8, uint16, uint32, float32, float64:
+		return nil
+
+	case amqp.Table:
+		for key, item := range typed {
+			if err := checkHeader(item); err != nil {
+				return fmt.Errorf("header `%s`: %w", key, err)
+			}
+		}
+
+		return nil
+
+	case []any:
+		for _, item := range typed {
+			if err := checkHeader(item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported type `%T`", value)
+	}
+}
+
+// decodeHeaders restores the headers with AMQP types: objects as amqp.Table and integers as int64, other numbers being float64.
+func decodeHeaders(content []byte) (amqp.Table, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	if raw == nil {
+		return nil, nil
+	}
+
+	return fromJSON(raw).(amqp.Table), nil
+}
+
+func fromJSON(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		table := make(amqp.Table, len(typed))
+		for key, item := range typed {
+			table[key] = fromJSON(item)
+		}
+
+		return table
+
+	case []any:
+		for index, item := range typed {
+			typed[index] = fromJSON(item)
+		}
+
+		return typed
+
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer
+		}
+
+		float, _ := typed.Float64()
+
+		return float
+
+	default:
+		return value
+	}
+}
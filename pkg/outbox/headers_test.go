@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		headers amqp.Table
+		want    amqp.Table
+		wantErr string
+	}{
+		"nil": {
+			nil,
+			nil,
+			"",
+		},
+		"nested": {
+			amqp.Table{"count": int32(3), "ratio": 0.5, "tags": []any{"a", 1}, "origin": amqp.Table{"retry": true}},
+			amqp.Table{"count": int64(3), "ratio": 0.5, "tags": []any{"a", int64(1)}, "origin": amqp.Table{"retry": true}},
+			"",
+		},
+		"bytes": {
+			amqp.Table{"signature": []byte("abc")},
+			nil,
+			"header `signature`: unsupported type `[]uint8`",
+		},
+		"time": {
+			amqp.Table{"origin": amqp.Table{"at": time.Now()}},
+			nil,
+			"header `origin`: header `at`: unsupported type `time.Time`",
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := encodeHeaders(testCase.headers)
+			if len(testCase.wantErr) != 0 {
+				assert.EqualError(t, err, testCase.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			got, err := decodeHeaders(content)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+			assert.NoError(t, got.Validate())
+		})
+	}
+}
@@ -0,0 +1,301 @@
+// Package outbox publishes AMQP messages atomically with the database writes of a transaction.
+//
+// Messages are inserted in an outbox table, then published by a relay. The table has to be created beforehand:
+//
+//	CREATE TABLE outbox (
+//	  id BIGSERIAL PRIMARY KEY,
+//	  exchange TEXT NOT NULL,
+//	  routing_key TEXT NOT NULL,
+//	  content_type TEXT NOT NULL DEFAULT '',
+//	  content_encoding TEXT NOT NULL DEFAULT '',
+//	  delivery_mode SMALLINT NOT NULL DEFAULT 0,
+//	  priority SMALLINT NOT NULL DEFAULT 0,
+//	  correlation_id TEXT NOT NULL DEFAULT '',
+//	  reply_to TEXT NOT NULL DEFAULT '',
+//	  expiration TEXT NOT NULL DEFAULT '',
+//	  message_id TEXT NOT NULL DEFAULT '',
+//	  message_timestamp TIMESTAMPTZ,
+//	  message_type TEXT NOT NULL DEFAULT '',
+//	  user_id TEXT NOT NULL DEFAULT '',
+//	  app_id TEXT NOT NULL DEFAULT '',
+//	  headers JSONB,
+//	  payload BYTEA NOT NULL,
+//	  retry INTEGER NOT NULL DEFAULT 0,
+//	  last_error TEXT,
+//	  next_attempt TIMESTAMPTZ NOT NULL DEFAULT now(),
+//	  creation_date TIMESTAMPTZ NOT NULL DEFAULT now(),
+//	  sent_date TIMESTAMPTZ
+//	);
+//
+//	CREATE INDEX outbox_pending ON outbox (next_attempt) WHERE sent_date IS NULL;
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/httputils/v4/pkg/cron"
+	"github.com/jackc/pgx/v5"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// messageColumns are the columns of the amqp.Publishing properties.
+const messageColumns = "content_type, content_encoding, delivery_mode, priority, correlation_id, reply_to, expiration, message_id, message_timestamp, message_type, user_id, app_id, headers, payload"
+
+type Database interface {
+	Create(ctx context.Context, query string, args ...any) (uint64, error)
+	List(ctx context.Context, scanner func(pgx.Rows) error, query string, args ...any) error
+	Exec(ctx context.Context, query string, args ...any) error
+}
+
+// Publisher publishes the messages, an amqp.Client with confirm mode enabled so a message is marked sent only once the broker has it.
+type Publisher interface {
+	Publish(ctx context.Context, payload amqp.Publishing, exchange, routingKey string) error
+}
+
+type Service struct {
+	db               Database
+	publisher        Publisher
+	semaphore        cron.Semaphore
+	table            string
+	name             string
+	interval         time.Duration
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+	retention        time.Duration
+	lockTimeout      time.Duration
+	batchSize        uint
+}
+
+type Config struct {
+	Table            string
+	Interval         time.Duration
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+	Retention        time.Duration
+	BatchSize        uint
+}
+
+func Flags(fs *flag.FlagSet, prefix string, overrides ...flags.Override) *Config {
+	var config Config
+
+	flags.New("Table", "Table of the messages").Prefix(prefix).DocPrefix("outbox").StringVar(fs, &config.Table, "outbox", overrides)
+	flags.New("Interval", "Interval between relays of the pending messages").Prefix(prefix).DocPrefix("outbox").DurationVar(fs, &config.Interval, 5*time.Second, overrides)
+	flags.New("BatchSize", "Messages read at once by the relay").Prefix(prefix).DocPrefix("outbox").UintVar(fs, &config.BatchSize, 100, overrides)
+	flags.New("RetryInterval", "Delay before the first retry of a message, doubled on each retry").Prefix(prefix).DocPrefix("outbox").DurationVar(fs, &config.RetryInterval, 10*time.Second, overrides)
+	flags.New("MaxRetryInterval", "Max delay between retries of a message").Prefix(prefix).DocPrefix("outbox").DurationVar(fs, &config.MaxRetryInterval, time.Hour, overrides)
+	flags.New("Retention", "Retention of the sent messages, 0 to keep them").Prefix(prefix).DocPrefix("outbox").DurationVar(fs, &config.Retention, 7*24*time.Hour, overrides)
+
+	return &config
+}
+
+func New(config *Config, db Database, publisher Publisher) (*Service, error) {
+	if len(config.Table) == 0 {
+		return nil, errors.New("table is required")
+	}
+
+	if config.Interval <= 0 {
+		return nil, fmt.Errorf("invalid interval `%s`", config.Interval)
+	}
+
+	if config.RetryInterval <= 0 || config.MaxRetryInterval < config.RetryInterval {
+		return nil, fmt.Errorf("invalid retry interval `%s` up to `%s`", config.RetryInterval, config.MaxRetryInterval)
+	}
+
+	if config.BatchSize == 0 {
+		return nil, fmt.Errorf("invalid batch size `%d`", config.BatchSize)
+	}
+
+	return &Service{
+		db:               db,
+		publisher:        publisher,
+		table:            pgx.Identifier{config.Table}.Sanitize(),
+		name:             "outbox:" + config.Table,
+		interval:         config.Interval,
+		retryInterval:    config.RetryInterval,
+		maxRetryInterval: config.MaxRetryInterval,
+		retention:        config.Retention,
+		batchSize:        config.BatchSize,
+	}, nil
+}
+
+// Exclusive runs the relay only on the instance holding the semaphore, keeping a single active relay between instances.
+func (s *Service) Exclusive(semaphore cron.Semaphore, timeout time.Duration) *Service {
+	s.semaphore = semaphore
+	s.lockTimeout = timeout
+
+	return s
+}
+
+// Publish inserts the message in the outbox, within the transaction of the context, e.g. in a db.Service.DoAtomic action. It fails with db.ErrNoTransaction outside of a transaction. Every property of the payload is kept, except DeliveryTag set by the broker. Headers are stored as JSON: []byte, time.Time and amqp.Decimal values are rejected, integers are published as int64.
+func (s *Service) Publish(ctx context.Context, payload amqp.Publishing, exchange, routingKey string) error {
+	headers, err := encodeHeaders(payload.Headers)
+	if err != nil {
+		return fmt.Errorf("encode headers: %w", err)
+	}
+
+	var timestamp *time.Time
+	if !payload.Timestamp.IsZero() {
+		timestamp = &payload.Timestamp
+	}
+
+	if _, err = s.db.Create(ctx, "INSERT INTO "+s.table+" (exchange, routing_key, "+messageColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING id",
+		exchange, routingKey, payload.ContentType, payload.ContentEncoding, int16(payload.DeliveryMode), int16(payload.Priority), payload.CorrelationId, payload.ReplyTo,
+		payload.Expiration, payload.MessageId, timestamp, payload.Type, payload.UserId, payload.AppId, headers, payload.Body); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) PublishJSON(ctx context.Context, item any, exchange, routingKey string) error {
+	payload, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	return s.Publish(ctx, amqp.Publishing{
+		ContentType: "application/json",
+		Body:        payload,
+	}, exchange, routingKey)
+}
+
+// Start relays the pending messages at each interval until the context is done.
+func (s *Service) Start(ctx context.Context) {
+	relay := cron.New().Each(s.interval).OnError(func(ctx context.Context, err error) {
+		slog.LogAttrs(ctx, slog.LevelError, "outbox relay", slog.String("table", s.table), slog.Any("error", err))
+	})
+
+	if s.semaphore != nil {
+		relay.Exclusive(s.semaphore, s.name, s.lockTimeout)
+	}
+
+	relay.Now().Start(ctx, s.Relay)
+}
+
+type message struct {
+	timestamp    *time.Time
+	publishing   amqp.Publishing
+	exchange     string
+	routingKey   string
+	headers      []byte
+	id           uint64
+	retry        int
+	deliveryMode int16
+	priority     int16
+}
+
+// Relay publishes the pending messages, marking them sent or scheduling their retry. Delivery is at least once: a crash between the publication and its marking publishes the message again, its MessageId being the outbox id for deduplication when not set.
+func (s *Service) Relay(ctx context.Context) error {
+	for {
+		messages, err := s.pending(ctx)
+		if err != nil {
+			return fmt.Errorf("list pending: %w", err)
+		}
+
+		for _, item := range messages {
+			if err := s.send(ctx, item); err != nil {
+				return err
+			}
+		}
+
+		if uint(len(messages)) < s.batchSize {
+			break
+		}
+	}
+
+	if s.retention > 0 {
+		if err := s.db.Exec(ctx, "DELETE FROM "+s.table+" WHERE sent_date < now() - make_interval(secs => $1)", s.retention.Seconds()); err != nil {
+			return fmt.Errorf("purge sent: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) pending(ctx context.Context) ([]message, error) {
+	var messages []message
+
+	scanner := func(rows pgx.Rows) error {
+		var item message
+
+		if err := rows.Scan(&item.id, &item.exchange, &item.routingKey, &item.publishing.ContentType, &item.publishing.ContentEncoding, &item.deliveryMode, &item.priority,
+			&item.publishing.CorrelationId, &item.publishing.ReplyTo, &item.publishing.Expiration, &item.publishing.MessageId, &item.timestamp, &item.publishing.Type,
+			&item.publishing.UserId, &item.publishing.AppId, &item.headers, &item.publishing.Body, &item.retry); err != nil {
+			return err
+		}
+
+		messages = append(messages, item)
+
+		return nil
+	}
+
+	if err := s.db.List(ctx, scanner, "SELECT id, exchange, routing_key, "+messageColumns+", retry FROM "+s.table+" WHERE sent_date IS NULL AND next_attempt <= now() ORDER BY id LIMIT $1", s.batchSize); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// send publishes the message, a failed publication being scheduled for a retry instead of returned.
+func (s *Service) send(ctx context.Context, item message) error {
+	payload := item.publishing
+	payload.DeliveryMode = uint8(item.deliveryMode)
+	payload.Priority = uint8(item.priority)
+
+	if item.timestamp != nil {
+		payload.Timestamp = *item.timestamp
+	}
+
+	if len(payload.MessageId) == 0 {
+		payload.MessageId = strconv.FormatUint(item.id, 10)
+	}
+
+	var err error
+
+	if len(item.headers) != 0 {
+		payload.Headers, err = decodeHeaders(item.headers)
+	}
+
+	if err == nil {
+		err = s.publisher.Publish(ctx, payload, item.exchange, item.routingKey)
+	}
+
+	if err == nil {
+		if err = s.db.Exec(ctx, "UPDATE "+s.table+" SET sent_date = now() WHERE id = $1", item.id); err != nil {
+			return fmt.Errorf("mark sent `%d`: %w", item.id, err)
+		}
+
+		return nil
+	}
+
+	delay := backoff(s.retryInterval, s.maxRetryInterval, item.retry)
+
+	slog.LogAttrs(ctx, slog.LevelWarn, "outbox publish", slog.Uint64("id", item.id), slog.Int("retry", item.retry), slog.Duration("delay", delay), slog.Any("error", err))
+
+	if err = s.db.Exec(ctx, "UPDATE "+s.table+" SET retry = retry + 1, last_error = $2, next_attempt = now() + make_interval(secs => $3) WHERE id = $1", item.id, err.Error(), delay.Seconds()); err != nil {
+		return fmt.Errorf("schedule retry `%d`: %w", item.id, err)
+	}
+
+	return nil
+}
+
+// backoff doubles the interval for each retry, up to the max.
+func backoff(interval, maxInterval time.Duration, retry int) time.Duration {
+	for range retry {
+		if interval >= maxInterval/2 {
+			return maxInterval
+		}
+
+		interval *= 2
+	}
+
+	return min(interval, maxInterval)
+}
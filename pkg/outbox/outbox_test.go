@@ -0,0 +1,232 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/db"
+	"github.com/ViBiOh/httputils/v4/pkg/mocks"
+	"github.com/jackc/pgx/v5"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeQuery struct {
+	query string
+	args  []any
+}
+
+type fakeDatabase struct {
+	rows     func() pgx.Rows
+	createFn func() error
+	pending  []message
+	queries  []fakeQuery
+}
+
+func (f *fakeDatabase) Create(_ context.Context, query string, args ...any) (uint64, error) {
+	f.queries = append(f.queries, fakeQuery{query, args})
+
+	if f.createFn != nil {
+		return 0, f.createFn()
+	}
+
+	return 1, nil
+}
+
+func (f *fakeDatabase) List(_ context.Context, scanner func(pgx.Rows) error, _ string, _ ...any) error {
+	pending := f.pending
+	f.pending = nil
+
+	for range pending {
+		if err := scanner(f.rows()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeDatabase) Exec(_ context.Context, query string, args ...any) error {
+	f.queries = append(f.queries, fakeQuery{query, args})
+
+	return nil
+}
+
+type fakePublisher struct {
+	published []amqp.Publishing
+}
+
+func (f *fakePublisher) Publish(_ context.Context, payload amqp.Publishing, _, routingKey string) error {
+	if routingKey == "unknown" {
+		return errors.New("NO_ROUTE")
+	}
+
+	f.published = append(f.published, payload)
+
+	return nil
+}
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+
+	fs := flag.NewFlagSet("simple", flag.ContinueOnError)
+	Flags(fs, "")
+
+	var writer strings.Builder
+	fs.SetOutput(&writer)
+	fs.Usage()
+
+	assert.Equal(t, `Usage of simple:
+  -batchSize uint
+    	[outbox] Messages read at once by the relay ${SIMPLE_BATCH_SIZE} (default 100)
+  -interval duration
+    	[outbox] Interval between relays of the pending messages ${SIMPLE_INTERVAL} (default 5s)
+  -maxRetryInterval duration
+    	[outbox] Max delay between retries of a message ${SIMPLE_MAX_RETRY_INTERVAL} (default 1h0m0s)
+  -retention duration
+    	[outbox] Retention of the sent messages, 0 to keep them ${SIMPLE_RETENTION} (default 168h0m0s)
+  -retryInterval duration
+    	[outbox] Delay before the first retry of a message, doubled on each retry ${SIMPLE_RETRY_INTERVAL} (default 10s)
+  -table string
+    	[outbox] Table of the messages ${SIMPLE_TABLE} (default "outbox")
+`, writer.String())
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(&Config{Table: "outbox", Interval: time.Second, RetryInterval: time.Minute, MaxRetryInterval: time.Second, BatchSize: 10}, nil, nil)
+	assert.ErrorContains(t, err, "invalid retry interval")
+
+	instance, err := New(&Config{Table: "outbox", Interval: time.Second, RetryInterval: time.Second, MaxRetryInterval: time.Minute, BatchSize: 10}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `"outbox"`, instance.table)
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	database := &fakeDatabase{}
+	instance, err := New(&Config{Table: "outbox", Interval: time.Second, RetryInterval: time.Second, MaxRetryInterval: time.Minute, BatchSize: 10}, database, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, instance.PublishJSON(context.Background(), map[string]int{"id": 1}, "users", "created"))
+	assert.Equal(t, []any{"users", "created", "application/json", "", int16(0), int16(0), "", "", "", "", (*time.Time)(nil), "", "", "", []byte("null"), []byte(`{"id":1}`)}, database.queries[0].args)
+
+	timestamp := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, instance.Publish(context.Background(), amqp.Publishing{
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: "abc",
+		MessageId:     "user-1",
+		Timestamp:     timestamp,
+		Body:          []byte("1"),
+	}, "users", "created"))
+	assert.Equal(t, []any{"users", "created", "", "", int16(2), int16(0), "abc", "", "", "user-1", &timestamp, "", "", "", []byte("null"), []byte("1")}, database.queries[1].args)
+
+	database.createFn = func() error { return db.ErrNoTransaction }
+	assert.ErrorIs(t, instance.PublishJSON(context.Background(), 1, "users", "created"), db.ErrNoTransaction)
+}
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	timestamp := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	pending := []message{
+		{id: 1, exchange: "users", routingKey: "created", deliveryMode: 2, timestamp: &timestamp, headers: []byte(`{"source":"test"}`), publishing: amqp.Publishing{ContentType: "application/json", CorrelationId: "abc", Body: []byte(`{"id":1}`)}},
+		{id: 2, exchange: "users", routingKey: "unknown", publishing: amqp.Publishing{ContentType: "application/json", MessageId: "user-2", Body: []byte(`{"id":2}`)}, retry: 3},
+	}
+
+	var index int
+
+	database := &fakeDatabase{
+		pending: pending,
+		rows: func() pgx.Rows {
+			item := pending[index]
+			index++
+
+			rows := mocks.NewRows(ctrl)
+			rows.EXPECT().Scan(gomock.Any()).DoAndReturn(func(pointers ...any) error {
+				*pointers[0].(*uint64) = item.id
+				*pointers[1].(*string) = item.exchange
+				*pointers[2].(*string) = item.routingKey
+				*pointers[3].(*string) = item.publishing.ContentType
+				*pointers[5].(*int16) = item.deliveryMode
+				*pointers[7].(*string) = item.publishing.CorrelationId
+				*pointers[10].(*string) = item.publishing.MessageId
+				*pointers[11].(**time.Time) = item.timestamp
+				*pointers[15].(*[]byte) = item.headers
+				*pointers[16].(*[]byte) = item.publishing.Body
+				*pointers[17].(*int) = item.retry
+
+				return nil
+			})
+
+			return rows
+		},
+	}
+
+	publisher := &fakePublisher{}
+
+	instance, err := New(&Config{Table: "outbox", Interval: time.Second, RetryInterval: time.Second, MaxRetryInterval: time.Minute, BatchSize: 10}, database, publisher)
+	assert.NoError(t, err)
+
+	assert.NoError(t, instance.Relay(context.Background()))
+
+	assert.Equal(t, []amqp.Publishing{{
+		ContentType:   "application/json",
+		Headers:       amqp.Table{"source": "test"},
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: "abc",
+		MessageId:     "1",
+		Timestamp:     timestamp,
+		Body:          []byte(`{"id":1}`),
+	}}, publisher.published)
+
+	assert.Len(t, database.queries, 2)
+	assert.Equal(t, []any{uint64(1)}, database.queries[0].args)
+	assert.Contains(t, database.queries[0].query, "sent_date = now()")
+	assert.Equal(t, []any{uint64(2), "NO_ROUTE", float64(8)}, database.queries[1].args)
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		retry int
+		want  time.Duration
+	}{
+		"first": {
+			0,
+			time.Second,
+		},
+		"doubled": {
+			3,
+			8 * time.Second,
+		},
+		"capped": {
+			10,
+			time.Minute,
+		},
+		"overflow": {
+			100,
+			time.Minute,
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.want, backoff(time.Second, time.Minute, testCase.retry))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	expiresAt time.Time
+	value     int64
+}
+
+// memoryStore keeps the counters of the instance, it mimics the Redis scripts when Redis is not available.
+type memoryStore struct {
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	mutex     sync.Mutex
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (m *memoryStore) get(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := m.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return memoryEntry{}, false
+	}
+
+	return entry, true
+}
+
+// sweep deletes the expired entries at most once per period.
+func (m *memoryStore) sweep(now time.Time, period time.Duration) {
+	if now.Sub(m.lastSweep) < period {
+		return
+	}
+
+	m.lastSweep = now
+
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func (l *Limiter) allowMemory(key string, now time.Time) Result {
+	l.memory.mutex.Lock()
+	defer l.memory.mutex.Unlock()
+
+	l.memory.sweep(now, l.period)
+
+	switch l.algorithm {
+	case FixedWindow:
+		redisKey := l.redisKey(key)
+
+		entry, ok := l.memory.get(redisKey, now)
+		if !ok {
+			entry.expiresAt = now.Add(l.period)
+		}
+
+		entry.value++
+		l.memory.entries[redisKey] = entry
+
+		return fixedWindowResult(l.limit, entry.value, entry.expiresAt.Sub(now))
+
+	case SlidingWindow:
+		index, elapsed := window(now, l.period)
+		currentKey := l.windowKey(key, index)
+
+		currentEntry, _ := l.memory.get(currentKey, now)
+		previousEntry, _ := l.memory.get(l.windowKey(key, index-1), now)
+
+		current, previous := currentEntry.value, previousEntry.value
+
+		allowed := slidingEstimate(l.period, elapsed, current, previous) < int64(l.limit)
+		if allowed {
+			current++
+			l.memory.entries[currentKey] = memoryEntry{value: current, expiresAt: now.Add(l.period * 2)}
+		}
+
+		return slidingWindowResult(l.limit, l.period, elapsed, allowed, current, previous)
+
+	default:
+		interval, tolerance := l.gcraParams()
+		redisKey := l.redisKey(key)
+
+		tat := now
+		if entry, ok := l.memory.get(redisKey, now); ok && entry.value > now.UnixMilli() {
+			tat = time.UnixMilli(entry.value)
+		}
+
+		newTat := tat.Add(interval)
+		allowAt := newTat.Add(-tolerance)
+
+		if now.Before(allowAt) {
+			return Result{
+				Limit:      l.limit,
+				RetryAfter: allowAt.Sub(now),
+				Reset:      tat.Sub(now),
+			}
+		}
+
+		l.memory.entries[redisKey] = memoryEntry{value: newTat.UnixMilli(), expiresAt: newTat}
+
+		return Result{
+			Allowed:   true,
+			Limit:     l.limit,
+			Remaining: int(now.Sub(allowAt) / interval),
+			Reset:     newTat.Sub(now),
+		}
+	}
+}
+
+// window returns the index of the fixed window containing now, and the duration elapsed since its start.
+func window(now time.Time, period time.Duration) (int64, time.Duration) {
+	milliseconds, periodMilliseconds := now.UnixMilli(), period.Milliseconds()
+
+	return milliseconds / periodMilliseconds, time.Duration(milliseconds%periodMilliseconds) * time.Millisecond
+}
+
+// gcraParams returns the emission interval between two requests, and the tolerance allowing a burst of the whole limit. Redis has a millisecond precision.
+func (l *Limiter) gcraParams() (time.Duration, time.Duration) {
+	interval := max((l.period / time.Duration(l.limit)).Truncate(time.Millisecond), time.Millisecond)
+
+	return interval, interval * time.Duration(l.limit)
+}
+
+func fixedWindowResult(limit int, count int64, ttl time.Duration) Result {
+	output := Result{
+		Allowed:   count <= int64(limit),
+		Limit:     limit,
+		Remaining: max(0, limit-int(count)),
+		Reset:     ttl,
+	}
+
+	if !output.Allowed {
+		output.RetryAfter = ttl
+	}
+
+	return output
+}
+
+func slidingEstimate(period, elapsed time.Duration, current, previous int64) int64 {
+	return previous*int64(period-elapsed)/int64(period) + current
+}
+
+func slidingWindowResult(limit int, period, elapsed time.Duration, allowed bool, current, previous int64) Result {
+	output := Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(0, limit-int(slidingEstimate(period, elapsed, current, previous))),
+		Reset:     period - elapsed,
+	}
+
+	if current > 0 {
+		// Requests of the current window still weigh during the next one.
+		output.Reset += period
+	}
+
+	if allowed {
+		return output
+	}
+
+	if free := int64(limit) - current; free > 0 && previous > 0 {
+		// The previous window weighs less as time passes, until it leaves room for a request.
+		output.RetryAfter = max(period-time.Duration(free*int64(period)/previous)-elapsed, 0) + time.Millisecond
+	} else {
+		output.RetryAfter = period - elapsed
+	}
+
+	return output
+}
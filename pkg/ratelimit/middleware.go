@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Middleware rejects requests over the limit with a 429, and sets the RateLimit headers of draft-ietf-httpapi-ratelimit-headers on every response.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	if !l.Enabled() {
+		return next
+	}
+
+	policy := fmt.Sprintf("%d;w=%d", l.limit, seconds(l.period))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := l.Allow(r.Context(), l.toKey(r))
+
+		headers := w.Header()
+		headers.Set("RateLimit-Policy", policy)
+		headers.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		headers.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		headers.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+		if !result.Allowed {
+			headers.Set("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		if next != nil {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// seconds rounds up, so clients don't retry too early.
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	instance, err := New(&Config{Algorithm: FixedWindow, Limit: 1, Period: time.Minute}, nil)
+	assert.NoError(t, err)
+
+	instance.WithKey(func(r *http.Request) string {
+		return r.Header.Get("X-Api-Key")
+	})
+
+	handler := instance.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Api-Key", key)
+
+		writer := httptest.NewRecorder()
+		handler.ServeHTTP(writer, req)
+
+		return writer
+	}
+
+	writer := request("first")
+	assert.Equal(t, http.StatusNoContent, writer.Code)
+	assert.Equal(t, "1;w=60", writer.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "1", writer.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", writer.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", writer.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, writer.Header().Get("Retry-After"))
+
+	writer = request("first")
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.NotEmpty(t, writer.Header().Get("Retry-After"))
+
+	writer = request("second")
+	assert.Equal(t, http.StatusNoContent, writer.Code)
+}
+
+func TestMiddlewareDisabled(t *testing.T) {
+	t.Parallel()
+
+	instance, err := New(&Config{Algorithm: GCRA, Period: time.Minute}, nil)
+	assert.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {})
+
+	writer := httptest.NewRecorder()
+	instance.Middleware(next).ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Empty(t, writer.Header().Get("RateLimit-Limit"))
+}
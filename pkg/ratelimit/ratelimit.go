@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/query"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	FixedWindow   = "fixed"
+	SlidingWindow = "sliding"
+	GCRA          = "gcra"
+)
+
+var _ model.Middleware = (&Limiter{}).Middleware
+
+type RedisClient interface {
+	Enabled() bool
+	RunScript(ctx context.Context, script *redis.Script, keys []string, args ...any) (any, error)
+}
+
+// Result is the outcome of a request against the limit.
+type Result struct {
+	Limit     int
+	Remaining int
+	// Reset is the duration until the quota is fully available again.
+	Reset time.Duration
+	// RetryAfter is the duration to wait before the next request is allowed, zero when allowed.
+	RetryAfter time.Duration
+	Allowed    bool
+}
+
+type Limiter struct {
+	redis     RedisClient
+	memory    *memoryStore
+	toKey     func(*http.Request) string
+	now       func() time.Time
+	algorithm string
+	prefix    string
+	period    time.Duration
+	limit     int
+}
+
+type Config struct {
+	Algorithm string
+	Prefix    string
+	Limit     int
+	Period    time.Duration
+}
+
+func Flags(fs *flag.FlagSet, prefix string, overrides ...flags.Override) *Config {
+	var config Config
+
+	flags.New("Algorithm", "Algorithm: fixed, sliding or gcra").Prefix(prefix).DocPrefix("ratelimit").StringVar(fs, &config.Algorithm, GCRA, overrides)
+	flags.New("Limit", "Requests allowed per period, 0 to disable").Prefix(prefix).DocPrefix("ratelimit").IntVar(fs, &config.Limit, 0, overrides)
+	flags.New("Period", "Period of the limit").Prefix(prefix).DocPrefix("ratelimit").DurationVar(fs, &config.Period, time.Minute, overrides)
+	flags.New("Prefix", "Prefix of the Redis keys").Prefix(prefix).DocPrefix("ratelimit").StringVar(fs, &config.Prefix, "ratelimit", overrides)
+
+	return &config
+}
+
+// New creates a limiter of config.Limit requests per period for each key. Counters are kept in Redis when enabled, in memory otherwise or when Redis fails.
+func New(config *Config, redisClient RedisClient) (*Limiter, error) {
+	switch config.Algorithm {
+	case FixedWindow, SlidingWindow, GCRA:
+	default:
+		return nil, fmt.Errorf("unknown algorithm `%s`", config.Algorithm)
+	}
+
+	if config.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit `%d`", config.Limit)
+	}
+
+	if config.Period <= 0 {
+		return nil, fmt.Errorf("invalid period `%s`", config.Period)
+	}
+
+	limiter := &Limiter{
+		memory:    newMemoryStore(),
+		toKey:     query.GetIP,
+		now:       time.Now,
+		algorithm: config.Algorithm,
+		prefix:    config.Prefix,
+		period:    config.Period,
+		limit:     config.Limit,
+	}
+
+	if !model.IsNil(redisClient) && redisClient.Enabled() {
+		limiter.redis = redisClient
+	}
+
+	return limiter, nil
+}
+
+// WithKey changes the key of the requests, the client IP by default.
+func (l *Limiter) WithKey(toKey func(*http.Request) string) *Limiter {
+	l.toKey = toKey
+
+	return l
+}
+
+func (l *Limiter) Enabled() bool {
+	return l.limit > 0
+}
+
+// Allow counts a request for the key. A disabled limiter allows every request.
+func (l *Limiter) Allow(ctx context.Context, key string) Result {
+	if !l.Enabled() {
+		return Result{Allowed: true}
+	}
+
+	now := l.now()
+
+	if l.redis != nil {
+		result, err := l.allowRedis(ctx, key, now)
+		if err == nil {
+			return result
+		}
+
+		slog.LogAttrs(ctx, slog.LevelError, "rate limit with redis, fallback to memory", slog.String("key", key), slog.Any("error", err))
+	}
+
+	return l.allowMemory(key, now)
+}
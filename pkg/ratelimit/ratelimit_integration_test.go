@@ -0,0 +1,42 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/ratelimit"
+	"github.com/ViBiOh/httputils/v4/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	integration := test.NewRedisIntegration(t)
+	integration.Bootstrap(context.Background(), "ratelimit")
+
+	defer integration.Close(context.Background())
+	defer integration.Reset()
+
+	for _, algorithm := range []string{ratelimit.FixedWindow, ratelimit.SlidingWindow, ratelimit.GCRA} {
+		t.Run(algorithm, func(t *testing.T) {
+			instance, err := ratelimit.New(&ratelimit.Config{Algorithm: algorithm, Prefix: "test", Limit: 3, Period: time.Minute}, integration.Client())
+			assert.NoError(t, err)
+
+			for index := range 3 {
+				result := instance.Allow(context.Background(), "127.0.0.1")
+
+				assert.True(t, result.Allowed)
+				assert.Equal(t, 2-index, result.Remaining)
+			}
+
+			result := instance.Allow(context.Background(), "127.0.0.1")
+
+			assert.False(t, result.Allowed)
+			assert.Positive(t, result.RetryAfter)
+		})
+	}
+}
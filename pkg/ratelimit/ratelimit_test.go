@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRedis struct {
+	err   error
+	reply any
+}
+
+func (f fakeRedis) Enabled() bool {
+	return true
+}
+
+func (f fakeRedis) RunScript(_ context.Context, _ *redis.Script, _ []string, _ ...any) (any, error) {
+	return f.reply, f.err
+}
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+
+	fs := flag.NewFlagSet("simple", flag.ContinueOnError)
+	Flags(fs, "")
+
+	var writer strings.Builder
+	fs.SetOutput(&writer)
+	fs.Usage()
+
+	assert.Equal(t, `Usage of simple:
+  -algorithm string
+    	[ratelimit] Algorithm: fixed, sliding or gcra ${SIMPLE_ALGORITHM} (default "gcra")
+  -limit int
+    	[ratelimit] Requests allowed per period, 0 to disable ${SIMPLE_LIMIT}
+  -period duration
+    	[ratelimit] Period of the limit ${SIMPLE_PERIOD} (default 1m0s)
+  -prefix string
+    	[ratelimit] Prefix of the Redis keys ${SIMPLE_PREFIX} (default "ratelimit")
+`, writer.String())
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(&Config{Algorithm: "unknown"}, nil)
+	assert.ErrorContains(t, err, "unknown algorithm")
+
+	_, err = New(&Config{Algorithm: GCRA, Limit: 10}, nil)
+	assert.ErrorContains(t, err, "invalid period")
+
+	_, err = New(&Config{Algorithm: GCRA}, nil)
+	assert.ErrorContains(t, err, "invalid period")
+
+	_, err = New(&Config{Algorithm: GCRA, Limit: -1, Period: time.Minute}, nil)
+	assert.ErrorContains(t, err, "invalid limit")
+
+	disabled, err := New(&Config{Algorithm: GCRA, Period: time.Minute}, nil)
+	assert.NoError(t, err)
+	assert.True(t, disabled.Allow(context.Background(), "127.0.0.1").Allowed)
+
+	instance, err := New(&Config{Algorithm: GCRA, Limit: 10, Period: time.Minute}, fakeRedis{})
+	assert.NoError(t, err)
+	assert.NotNil(t, instance.redis)
+}
+
+func TestAllowMemory(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	type step struct {
+		offset time.Duration
+		want   Result
+	}
+
+	cases := map[string]struct {
+		algorithm string
+		steps     []step
+	}{
+		"fixed": {
+			algorithm: FixedWindow,
+			steps: []step{
+				{0, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Minute}},
+				{time.Second * 30, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second * 30}},
+				{time.Second * 40, Result{Limit: 2, Reset: time.Second * 20, RetryAfter: time.Second * 20}},
+				{time.Minute, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Minute}},
+			},
+		},
+		"sliding": {
+			algorithm: SlidingWindow,
+			steps: []step{
+				{0, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Minute * 2}},
+				{time.Second * 30, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second * 90}},
+				{time.Minute, Result{Limit: 2, Reset: time.Minute, RetryAfter: time.Millisecond}},
+				{time.Second * 90, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second * 90}},
+			},
+		},
+		"gcra": {
+			algorithm: GCRA,
+			steps: []step{
+				{0, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second * 30}},
+				{0, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}},
+				{time.Second * 10, Result{Limit: 2, Reset: time.Second * 50, RetryAfter: time.Second * 20}},
+				{time.Second * 30, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}},
+			},
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			instance, err := New(&Config{Algorithm: testCase.algorithm, Limit: 2, Period: time.Minute}, nil)
+			assert.NoError(t, err)
+
+			for index, step := range testCase.steps {
+				instance.now = func() time.Time { return start.Add(step.offset) }
+
+				assert.Equal(t, step.want, instance.Allow(context.Background(), "127.0.0.1"), "step %d", index)
+			}
+		})
+	}
+}
+
+func TestAllowRedis(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		redis fakeRedis
+		want  Result
+	}{
+		"reply": {
+			redis: fakeRedis{reply: []any{int64(0), int64(0), int64(1500), int64(60000)}},
+			want:  Result{Limit: 2, RetryAfter: time.Millisecond * 1500, Reset: time.Minute},
+		},
+		"fallback on error": {
+			redis: fakeRedis{err: errors.New("failed")},
+			want:  Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second * 30},
+		},
+		"fallback on invalid reply": {
+			redis: fakeRedis{reply: "OK"},
+			want:  Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second * 30},
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			instance, err := New(&Config{Algorithm: GCRA, Limit: 2, Period: time.Minute}, testCase.redis)
+			assert.NoError(t, err)
+
+			assert.Equal(t, testCase.want, instance.Allow(context.Background(), "127.0.0.1"))
+		})
+	}
+}
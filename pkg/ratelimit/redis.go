@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var errUnexpectedReply = errors.New("unexpected script reply")
+
+// fixedWindowScript counts the request in a window starting with the first request. It returns the count and the remaining milliseconds of the window.
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+  redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// slidingWindowScript weights the previous window count by its remaining overlap with the sliding window. Rejected requests are not counted. It returns whether it's allowed, and the counts of the current and previous windows.
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+local previous = tonumber(redis.call("GET", KEYS[2]) or "0")
+
+if math.floor(previous * (period - elapsed) / period) + current >= limit then
+  return {0, current, previous}
+end
+
+current = redis.call("INCR", KEYS[1])
+redis.call("PEXPIRE", KEYS[1], period * 2)
+
+return {1, current, previous}
+`)
+
+// gcraScript stores the theoretical arrival time of the next request. It returns whether it's allowed, the remaining requests, the retry after and the reset in milliseconds.
+var gcraScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local tolerance = tonumber(ARGV[3])
+
+local tat = math.max(tonumber(redis.call("GET", KEYS[1]) or ARGV[1]), now)
+local newTat = tat + interval
+local allowAt = newTat - tolerance
+
+if now < allowAt then
+  return {0, 0, allowAt - now, tat - now}
+end
+
+redis.call("SET", KEYS[1], newTat, "PX", newTat - now)
+
+return {1, math.floor((now - allowAt) / interval), 0, newTat - now}
+`)
+
+func (l *Limiter) allowRedis(ctx context.Context, key string, now time.Time) (Result, error) {
+	switch l.algorithm {
+	case FixedWindow:
+		reply, err := l.runScript(ctx, fixedWindowScript, []string{l.redisKey(key)}, l.period.Milliseconds())
+		if err != nil {
+			return Result{}, err
+		}
+
+		return fixedWindowResult(l.limit, reply[0], time.Duration(reply[1])*time.Millisecond), nil
+
+	case SlidingWindow:
+		index, elapsed := window(now, l.period)
+
+		reply, err := l.runScript(ctx, slidingWindowScript, []string{l.windowKey(key, index), l.windowKey(key, index-1)}, l.limit, l.period.Milliseconds(), elapsed.Milliseconds())
+		if err != nil {
+			return Result{}, err
+		}
+
+		return slidingWindowResult(l.limit, l.period, elapsed, reply[0] == 1, reply[1], reply[2]), nil
+
+	default:
+		interval, tolerance := l.gcraParams()
+
+		reply, err := l.runScript(ctx, gcraScript, []string{l.redisKey(key)}, now.UnixMilli(), interval.Milliseconds(), tolerance.Milliseconds())
+		if err != nil {
+			return Result{}, err
+		}
+
+		return Result{
+			Allowed:    reply[0] == 1,
+			Limit:      l.limit,
+			Remaining:  int(reply[1]),
+			RetryAfter: time.Duration(reply[2]) * time.Millisecond,
+			Reset:      time.Duration(reply[3]) * time.Millisecond,
+		}, nil
+	}
+}
+
+func (l *Limiter) runScript(ctx context.Context, script *redis.Script, keys []string, args ...any) ([]int64, error) {
+	reply, err := l.redis.RunScript(ctx, script, keys, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnexpectedReply, reply)
+	}
+
+	output := make([]int64, len(values))
+
+	for index, value := range values {
+		if output[index], ok = value.(int64); !ok {
+			return nil, fmt.Errorf("%w: %T at %d", errUnexpectedReply, value, index)
+		}
+	}
+
+	return output, nil
+}
+
+func (l *Limiter) redisKey(key string) string {
+	return fmt.Sprintf("%s:%s:%s", l.prefix, l.algorithm, key)
+}
+
+// windowKey uses the key as hash tag, so both windows live on the same cluster slot.
+func (l *Limiter) windowKey(key string, index int64) string {
+	return fmt.Sprintf("%s:%s:{%s}:%d", l.prefix, l.algorithm, key, index)
+}
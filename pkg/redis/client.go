@@ -9,6 +9,11 @@ import (
 
 type Client interface {
 	Subscriber
+	PSubscriber
+	SSubscriber
+	Streamer
+	Hasher
+	SortedSetter
 
 	Enabled() bool
 	Close(context.Context)
@@ -22,14 +27,30 @@ type Client interface {
 	DeletePattern(ctx context.Context, pattern string) error
 	Scan(ctx context.Context, pattern string, output chan<- string, pageSize int64) error
 	Exclusive(ctx context.Context, name string, timeout time.Duration, action func(context.Context) error) (bool, error)
+	Lock(ctx context.Context, name string, ttl time.Duration, action func(ctx context.Context, fencing int64) error) (bool, error)
 	Expire(ctx context.Context, ttl time.Duration, keys ...string) error
+	TTL(ctx context.Context, key string) (time.Duration, bool, error)
+	Tag(ctx context.Context, tagged map[string][]string, ttl time.Duration) error
+	DeleteTag(ctx context.Context, tag string) error
 	Push(ctx context.Context, key string, value any) error
 	Pull(ctx context.Context, key string, handler func(string, error))
+	PullReliable(ctx context.Context, key string, config ReliableConfig, handler func(context.Context, Delivery, error))
 	Publish(ctx context.Context, channel string, value any) error
 	PublishJSON(ctx context.Context, channel string, value any) error
+	SPublish(ctx context.Context, channel string, value any) error
+	XAdd(ctx context.Context, stream string, maxLen int64, value any) (string, error)
+	RunScript(ctx context.Context, script *redis.Script, keys []string, args ...any) (any, error)
 	Pipeline() redis.Pipeliner
 }
 
 type Subscriber interface {
-	Subscribe(ctx context.Context, channel string) (<-chan *redis.Message, func(context.Context))
+	Subscribe(ctx context.Context, channel string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context))
+}
+
+type PSubscriber interface {
+	PSubscribe(ctx context.Context, pattern string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context))
+}
+
+type SSubscriber interface {
+	SSubscribe(ctx context.Context, channel string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context))
 }
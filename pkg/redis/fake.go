@@ -0,0 +1,639 @@
+package redis
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	_ Client = &Fake{}
+
+	// ErrUnsupported is returned by the Fake for the features it can't emulate, e.g. Lua scripts.
+	ErrUnsupported = errors.New("not supported by fake")
+
+	errWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+)
+
+type fakeEntry struct {
+	expiresAt time.Time
+	// value is a string, a list, a hash, a sorted set or a stream.
+	value any
+}
+
+type (
+	fakeList []string
+	fakeHash map[string]string
+	fakeZSet map[string]float64
+)
+
+// Fake is an in-memory Client with the semantics of Redis, for tests that don't have a server. Keys expire according to its own clock, moved forward with Advance. Lua scripts and pipelines are not supported.
+type Fake struct {
+	now           func() time.Time
+	entries       map[string]*fakeEntry
+	subscriptions map[*fakeSubscription]struct{}
+	// changed is closed and replaced on every write of a list or a stream, waking up the blocking reads.
+	changed chan struct{}
+	offset  time.Duration
+	mutex   sync.Mutex
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		now:           time.Now,
+		entries:       make(map[string]*fakeEntry),
+		subscriptions: make(map[*fakeSubscription]struct{}),
+		changed:       make(chan struct{}),
+	}
+}
+
+// Advance moves the clock of the fake forward, expiring keys accordingly.
+func (f *Fake) Advance(duration time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.offset += duration
+}
+
+// Now is the current time of the fake clock.
+func (f *Fake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.clock()
+}
+
+func (f *Fake) clock() time.Time {
+	return f.now().Add(f.offset)
+}
+
+// get returns the entry of the key, deleting it if it's expired. The mutex has to be held.
+func (f *Fake) get(key string) (*fakeEntry, bool) {
+	entry, ok := f.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !entry.expiresAt.IsZero() && !f.clock().Before(entry.expiresAt) {
+		delete(f.entries, key)
+
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// getAs returns the value of the key if it has the expected type, or errWrongType. The mutex has to be held.
+func getAs[T any](f *Fake, key string) (T, bool, error) {
+	var output T
+
+	entry, ok := f.get(key)
+	if !ok {
+		return output, false, nil
+	}
+
+	output, ok = entry.value.(T)
+	if !ok {
+		return output, false, errWrongType
+	}
+
+	return output, true, nil
+}
+
+// set stores the value, keeping the expiration of the key if it already exists. The mutex has to be held.
+func (f *Fake) set(key string, value any) {
+	if entry, ok := f.get(key); ok {
+		entry.value = value
+
+		return
+	}
+
+	f.entries[key] = &fakeEntry{value: value}
+}
+
+// expire sets the time to live of the key, deleting it when not positive like Redis. The mutex has to be held.
+func (f *Fake) expire(key string, ttl time.Duration) {
+	entry, ok := f.get(key)
+	if !ok {
+		return
+	}
+
+	if ttl <= 0 {
+		delete(f.entries, key)
+
+		return
+	}
+
+	entry.expiresAt = f.clock().Add(ttl)
+}
+
+// notify wakes up the blocking reads. The mutex has to be held.
+func (f *Fake) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// wait blocks until a list or a stream changes. It returns false if the context is done meanwhile.
+func (f *Fake) wait(ctx context.Context, changed <-chan struct{}) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-changed:
+		return true
+	}
+}
+
+// fakeString converts the value like the Redis client does when sending it.
+func fakeString(value any) (string, error) {
+	switch content := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return content, nil
+	case []byte:
+		return string(content), nil
+	case int:
+		return strconv.Itoa(content), nil
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", content), nil
+	case float32:
+		return strconv.FormatFloat(float64(content), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(content, 'f', -1, 64), nil
+	case bool:
+		if content {
+			return "1", nil
+		}
+
+		return "0", nil
+	case time.Time:
+		return content.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(content.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		payload, err := content.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+
+		return string(payload), nil
+	default:
+		return "", fmt.Errorf("can't marshal %T (implement encoding.BinaryMarshaler)", value)
+	}
+}
+
+func (f *Fake) Enabled() bool {
+	return true
+}
+
+// Close ends the subscriptions.
+func (f *Fake) Close(_ context.Context) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for subscription := range f.subscriptions {
+		f.unsubscribe(subscription)
+	}
+}
+
+func (f *Fake) FlushAll(_ context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	clear(f.entries)
+	f.notify()
+
+	return nil
+}
+
+func (f *Fake) Ping(_ context.Context) error {
+	return nil
+}
+
+func (f *Fake) Load(_ context.Context, key string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	content, ok, err := getAs[string](f, key)
+	if err != nil {
+		return nil, fmt.Errorf("exec get: %w", err)
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	return []byte(content), nil
+}
+
+// LoadMany returns an empty string for the missing keys, like MGET.
+func (f *Fake) LoadMany(_ context.Context, keys ...string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	output := make([]string, len(keys))
+
+	for index, key := range keys {
+		if entry, ok := f.get(key); ok {
+			output[index], _ = entry.value.(string)
+		}
+	}
+
+	return output, nil
+}
+
+func (f *Fake) Store(ctx context.Context, key string, value any, ttl time.Duration) error {
+	return f.StoreMany(ctx, map[string]any{key: value}, ttl)
+}
+
+// StoreMany sets the values, overriding their type and expiration like SET. A zero ttl doesn't expire.
+func (f *Fake) StoreMany(_ context.Context, values map[string]any, ttl time.Duration) error {
+	contents := make(map[string]string, len(values))
+
+	for key, value := range values {
+		content, err := fakeString(value)
+		if err != nil {
+			return fmt.Errorf("set `%s`: %w", key, err)
+		}
+
+		contents[key] = content
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for key, content := range contents {
+		entry := fakeEntry{value: content}
+
+		if ttl > 0 {
+			entry.expiresAt = f.clock().Add(ttl)
+		}
+
+		f.entries[key] = &entry
+	}
+
+	return nil
+}
+
+func (f *Fake) Delete(_ context.Context, keys ...string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, key := range keys {
+		delete(f.entries, key)
+	}
+
+	return nil
+}
+
+func (f *Fake) DeletePattern(_ context.Context, pattern string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, key := range f.keys(pattern) {
+		delete(f.entries, key)
+	}
+
+	return nil
+}
+
+// keys returns the sorted keys matching the glob-style pattern. The mutex has to be held.
+func (f *Fake) keys(pattern string) []string {
+	var output []string
+
+	for key := range f.entries {
+		if _, ok := f.get(key); ok && globMatch(pattern, key) {
+			output = append(output, key)
+		}
+	}
+
+	slices.Sort(output)
+
+	return output
+}
+
+// Scan sends the keys matching the glob-style pattern, e.g. `user:*`, `user:?` or `user:[0-9]`.
+func (f *Fake) Scan(ctx context.Context, pattern string, output chan<- string, _ int64) error {
+	defer close(output)
+
+	f.mutex.Lock()
+	keys := f.keys(pattern)
+	f.mutex.Unlock()
+
+	for _, key := range keys {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("exec scan: %w", ctx.Err())
+		case output <- key:
+		}
+	}
+
+	return nil
+}
+
+func (f *Fake) Exclusive(ctx context.Context, name string, timeout time.Duration, action func(context.Context) error) (bool, error) {
+	return exclusive(ctx, f, name, timeout, action)
+}
+
+// Lock renews the lease in real time, the lock is lost when the fake clock is moved beyond its ttl between two renewals.
+func (f *Fake) Lock(ctx context.Context, name string, ttl time.Duration, action func(ctx context.Context, fencing int64) error) (bool, error) {
+	return lockWith(ctx, f, name, ttl, action)
+}
+
+func (f *Fake) acquire(_ context.Context, lock lease, ttl time.Duration) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.get(lock.name); ok {
+		return 0, nil
+	}
+
+	f.entries[lock.name] = &fakeEntry{value: lock.token, expiresAt: f.clock().Add(ttl)}
+
+	if len(lock.fencing) == 0 {
+		return 1, nil
+	}
+
+	return f.incrBy(lock.fencing, 1)
+}
+
+func (f *Fake) release(_ context.Context, lock lease) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.owns(lock) {
+		delete(f.entries, lock.name)
+	}
+
+	return nil
+}
+
+func (f *Fake) extend(_ context.Context, lock lease, ttl time.Duration) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !f.owns(lock) {
+		return false, nil
+	}
+
+	f.expire(lock.name, ttl)
+
+	return true, nil
+}
+
+// owns checks that the lock is still held with the token of the lease. The mutex has to be held.
+func (f *Fake) owns(lock lease) bool {
+	token, ok, _ := getAs[string](f, lock.name)
+
+	return ok && token == lock.token
+}
+
+// incrBy increments the integer of the key, like INCRBY. The mutex has to be held.
+func (f *Fake) incrBy(key string, incr int64) (int64, error) {
+	content, _, err := getAs[string](f, key)
+	if err != nil {
+		return 0, err
+	}
+
+	var value int64
+
+	if len(content) != 0 {
+		if value, err = strconv.ParseInt(content, 10, 64); err != nil {
+			return 0, errors.New("ERR value is not an integer or out of range")
+		}
+	}
+
+	value += incr
+	f.set(key, strconv.FormatInt(value, 10))
+
+	return value, nil
+}
+
+func (f *Fake) Expire(_ context.Context, ttl time.Duration, keys ...string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, key := range keys {
+		f.expire(key, ttl)
+	}
+
+	return nil
+}
+
+func (f *Fake) TTL(_ context.Context, key string) (time.Duration, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entry, ok := f.get(key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	if entry.expiresAt.IsZero() {
+		return 0, true, nil
+	}
+
+	// Redis has a second precision.
+	return entry.expiresAt.Sub(f.clock()).Round(time.Second), true, nil
+}
+
+func (f *Fake) Tag(_ context.Context, tagged map[string][]string, ttl time.Duration) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := f.clock()
+	score := tagExpiration(now, ttl)
+
+	for tag, keys := range tagged {
+		members, err := f.sortedSet(tag)
+		if err != nil {
+			return fmt.Errorf("zadd `%s`: %w", tag, err)
+		}
+
+		// Like ZADD GT, the expiration of a member is only extended.
+		for _, key := range keys {
+			if current, ok := members[key]; !ok || current < score {
+				members[key] = score
+			}
+		}
+
+		latest := math.Inf(-1)
+
+		for key, expiration := range members {
+			if expiration < float64(now.UnixMilli()) {
+				delete(members, key)
+			} else {
+				latest = max(latest, expiration)
+			}
+		}
+
+		// Like the expire tag script, the set expires with its longest-lived member.
+		switch {
+		case len(members) == 0:
+			delete(f.entries, tag)
+		case math.IsInf(latest, 1):
+			f.entries[tag].expiresAt = time.Time{}
+		default:
+			f.entries[tag].expiresAt = time.UnixMilli(int64(latest))
+		}
+	}
+
+	return nil
+}
+
+func (f *Fake) DeleteTag(_ context.Context, tag string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	members, _, err := getAs[fakeZSet](f, tag)
+	if err != nil {
+		return fmt.Errorf("zrange `%s`: %w", tag, err)
+	}
+
+	for key := range members {
+		delete(f.entries, key)
+	}
+
+	delete(f.entries, tag)
+
+	return nil
+}
+
+func (f *Fake) RunScript(_ context.Context, _ *redis.Script, _ []string, _ ...any) (any, error) {
+	return nil, fmt.Errorf("exec script: %w", ErrUnsupported)
+}
+
+// Pipeline is not supported, it returns nil.
+func (f *Fake) Pipeline() redis.Pipeliner {
+	return nil
+}
+
+// globMatch matches the name against the glob-style pattern of Redis: `*`, `?`, `[abc]`, `[^a-z]` and `\` to escape.
+func globMatch(pattern, name string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+
+			if len(pattern) == 1 {
+				return true
+			}
+
+			for index := range len(name) + 1 {
+				if globMatch(pattern[1:], name[index:]) {
+					return true
+				}
+			}
+
+			return false
+
+		case '?':
+			if len(name) == 0 {
+				return false
+			}
+
+			name = name[1:]
+
+		case '[':
+			if len(name) == 0 {
+				return false
+			}
+
+			end := classEnd(pattern)
+			if end < 0 {
+				// An unclosed class is a literal.
+				if name[0] != '[' {
+					return false
+				}
+
+				name = name[1:]
+
+				break
+			}
+
+			if !matchClass(pattern[1:end], name[0]) {
+				return false
+			}
+
+			pattern = pattern[end:]
+			name = name[1:]
+
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+
+			fallthrough
+
+		default:
+			if len(name) == 0 || pattern[0] != name[0] {
+				return false
+			}
+
+			name = name[1:]
+		}
+
+		pattern = pattern[1:]
+	}
+
+	return len(name) == 0
+}
+
+// classEnd returns the index of the closing bracket of the class starting the pattern, -1 if it's not closed.
+func classEnd(pattern string) int {
+	for index := 1; index < len(pattern); index++ {
+		switch pattern[index] {
+		case '\\':
+			index++
+		case ']':
+			return index
+		}
+	}
+
+	return -1
+}
+
+func matchClass(class string, char byte) bool {
+	negate := len(class) > 0 && class[0] == '^'
+	if negate {
+		class = class[1:]
+	}
+
+	var match bool
+
+	for index := 0; index < len(class) && !match; index++ {
+		switch {
+		case class[index] == '\\' && index+1 < len(class):
+			index++
+			match = class[index] == char
+
+		case index+2 < len(class) && class[index+1] == '-':
+			low, high := class[index], class[index+2]
+			if low > high {
+				low, high = high, low
+			}
+
+			match = char >= low && char <= high
+			index += 2
+
+		default:
+			match = class[index] == char
+		}
+	}
+
+	return match != negate
+}
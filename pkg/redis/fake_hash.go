@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+)
+
+// hash returns the hash of the key, creating it if asked. The mutex has to be held.
+func (f *Fake) hash(key string, create bool) (fakeHash, error) {
+	hash, ok, err := getAs[fakeHash](f, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok && create {
+		hash = make(fakeHash)
+		f.set(key, hash)
+	}
+
+	return hash, nil
+}
+
+// hincrBy increments the integer of the field, like HINCRBY. The mutex has to be held.
+func (f *Fake) hincrBy(key, field string, incr int64) (int64, error) {
+	hash, err := f.hash(key, true)
+	if err != nil {
+		return 0, err
+	}
+
+	var value int64
+
+	if content, ok := hash[field]; ok {
+		if value, err = strconv.ParseInt(content, 10, 64); err != nil {
+			return 0, errors.New("ERR hash value is not an integer")
+		}
+	}
+
+	value += incr
+	hash[field] = strconv.FormatInt(value, 10)
+
+	return value, nil
+}
+
+// hdel deletes the fields, and the hash once empty. The mutex has to be held.
+func (f *Fake) hdel(key string, fields ...string) error {
+	hash, err := f.hash(key, false)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		delete(hash, field)
+	}
+
+	if hash != nil && len(hash) == 0 {
+		delete(f.entries, key)
+	}
+
+	return nil
+}
+
+func (f *Fake) HGet(_ context.Context, key, field string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	hash, err := f.hash(key, false)
+	if err != nil {
+		return nil, fmt.Errorf("hget: %w", err)
+	}
+
+	content, ok := hash[field]
+	if !ok {
+		return nil, nil
+	}
+
+	return []byte(content), nil
+}
+
+func (f *Fake) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	hash, err := f.hash(key, false)
+	if err != nil {
+		return nil, fmt.Errorf("hgetall: %w", err)
+	}
+
+	output := make(map[string]string, len(hash))
+	maps.Copy(output, hash)
+
+	return output, nil
+}
+
+func (f *Fake) HSet(_ context.Context, key string, values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	hash, err := f.hash(key, true)
+	if err != nil {
+		return fmt.Errorf("hset: %w", err)
+	}
+
+	for field, value := range values {
+		content, err := fakeString(value)
+		if err != nil {
+			return fmt.Errorf("hset `%s`: %w", field, err)
+		}
+
+		hash[field] = content
+	}
+
+	return nil
+}
+
+func (f *Fake) HIncrBy(_ context.Context, key, field string, incr int64) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	value, err := f.hincrBy(key, field, incr)
+	if err != nil {
+		return 0, fmt.Errorf("hincrby: %w", err)
+	}
+
+	return value, nil
+}
+
+func (f *Fake) HDel(_ context.Context, key string, fields ...string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.hdel(key, fields...); err != nil {
+		return fmt.Errorf("hdel: %w", err)
+	}
+
+	return nil
+}
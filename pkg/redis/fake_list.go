@@ -0,0 +1,246 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// setList stores the list, an empty list being deleted like Redis does. The mutex has to be held.
+func (f *Fake) setList(key string, list fakeList) {
+	if len(list) == 0 {
+		delete(f.entries, key)
+	} else {
+		f.set(key, list)
+	}
+
+	f.notify()
+}
+
+// lpush prepends the values to the list, the head of the list being its first item. The mutex has to be held.
+func (f *Fake) lpush(key string, values ...string) error {
+	list, _, err := getAs[fakeList](f, key)
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		list = slices.Insert(list, 0, value)
+	}
+
+	f.setList(key, list)
+
+	return nil
+}
+
+// rpop removes the last item of the list. The mutex has to be held.
+func (f *Fake) rpop(key string) (string, bool, error) {
+	list, _, err := getAs[fakeList](f, key)
+	if err != nil || len(list) == 0 {
+		return "", false, err
+	}
+
+	value := list[len(list)-1]
+	f.setList(key, list[:len(list)-1])
+
+	return value, true, nil
+}
+
+// lremLast removes the last occurrence of the value in the list, like LREM with a -1 count. The mutex has to be held.
+func (f *Fake) lremLast(key, value string) (bool, error) {
+	list, _, err := getAs[fakeList](f, key)
+	if err != nil {
+		return false, err
+	}
+
+	for index := len(list) - 1; index >= 0; index-- {
+		if list[index] == value {
+			f.setList(key, slices.Delete(list, index, index+1))
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (f *Fake) Push(_ context.Context, key string, value any) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.lpush(key, string(content)); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+
+	return nil
+}
+
+func (f *Fake) Pull(ctx context.Context, key string, handler func(string, error)) {
+	var wait backoff
+
+	for {
+		f.mutex.Lock()
+		content, ok, err := f.rpop(key)
+		changed := f.changed
+		f.mutex.Unlock()
+
+		if err != nil {
+			handler("", err)
+
+			if !wait.sleep(ctx) {
+				return
+			}
+
+			continue
+		}
+
+		if ok {
+			handler(content, nil)
+
+			continue
+		}
+
+		if !f.wait(ctx, changed) {
+			return
+		}
+	}
+}
+
+// PullReliable delivers the messages like Service.PullReliable. There is no heartbeat in a single process, so the messages of a consumer are delivered again only when it restarts.
+func (f *Fake) PullReliable(ctx context.Context, key string, config ReliableConfig, handler func(context.Context, Delivery, error)) {
+	if err := config.validate(); err != nil {
+		handler(ctx, Delivery{}, err)
+
+		return
+	}
+
+	keys := newReliableKeys(key, config.Consumer)
+
+	f.mutex.Lock()
+	err := f.recoverProcessing(keys, config.MaxRetry)
+	f.mutex.Unlock()
+
+	if err != nil {
+		handler(ctx, Delivery{}, fmt.Errorf("recover: %w", err))
+	}
+
+	var wait backoff
+
+	for {
+		f.mutex.Lock()
+		delivery, ok, err := f.deliver(keys, config.MaxRetry)
+		changed := f.changed
+		f.mutex.Unlock()
+
+		if err != nil {
+			handler(ctx, Delivery{}, fmt.Errorf("blmove: %w", err))
+
+			if !wait.sleep(ctx) {
+				return
+			}
+
+			continue
+		}
+
+		if ok {
+			handler(ctx, delivery, nil)
+
+			continue
+		}
+
+		if !f.wait(ctx, changed) {
+			return
+		}
+	}
+}
+
+// deliver moves the next message of the queue to the processing list. The mutex has to be held.
+func (f *Fake) deliver(keys reliableKeys, maxRetry int) (Delivery, bool, error) {
+	element, ok, err := f.rpop(keys.queue)
+	if err != nil || !ok {
+		return Delivery{}, false, err
+	}
+
+	if err := f.lpush(keys.processing, element); err != nil {
+		return Delivery{}, false, err
+	}
+
+	payload, retry := parseElement(element)
+
+	return Delivery{
+		Payload: payload,
+		Retry:   retry,
+		ack: func(_ context.Context) error {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+
+			if _, err := f.lremLast(keys.processing, element); err != nil {
+				return fmt.Errorf("ack: %w", err)
+			}
+
+			return nil
+		},
+		nack: func(_ context.Context) error {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+
+			removed, err := f.lremLast(keys.processing, element)
+			if err != nil {
+				return fmt.Errorf("nack: %w", err)
+			}
+
+			if !removed {
+				return nil
+			}
+
+			return f.retry(keys, maxRetry, payload, retry+1, f.lpush)
+		},
+	}, true, nil
+}
+
+// retry requeues the payload for the given retry with the given push, or moves it to the dead-letter list beyond the max retry. The mutex has to be held.
+func (f *Fake) retry(keys reliableKeys, maxRetry int, payload string, retry int, push func(string, ...string) error) error {
+	if maxRetry > 0 && retry > maxRetry {
+		return f.lpush(keys.dead, payload)
+	}
+
+	return push(keys.queue, retryElement(payload, retry))
+}
+
+// recoverProcessing moves back the processing list of the consumer to the queue, counting a retry for each message. The mutex has to be held.
+func (f *Fake) recoverProcessing(keys reliableKeys, maxRetry int) error {
+	list, _, err := getAs[fakeList](f, keys.processing)
+	if err != nil {
+		return err
+	}
+
+	delete(f.entries, keys.processing)
+
+	for _, element := range list {
+		payload, retry := parseElement(element)
+
+		if err := f.retry(keys, maxRetry, payload, retry+1, f.rpush); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rpush appends the values to the list. The mutex has to be held.
+func (f *Fake) rpush(key string, values ...string) error {
+	list, _, err := getAs[fakeList](f, key)
+	if err != nil {
+		return err
+	}
+
+	f.setList(key, append(list, values...))
+
+	return nil
+}
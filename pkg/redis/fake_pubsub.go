@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeChannelSize is the buffer of a subscription, messages are dropped when it's full like the Redis client does.
+const fakeChannelSize = 100
+
+type fakeSubscription struct {
+	ctx    context.Context
+	output chan *redis.Message
+	config subscribeConfig
+	kind   string
+	name   string
+}
+
+func (fs *fakeSubscription) match(kind, channel string) (*redis.Message, bool) {
+	switch {
+	case kind == "publish" && fs.kind == "subscribe" && fs.name == channel:
+		return &redis.Message{Channel: channel}, true
+	case kind == "publish" && fs.kind == "psubscribe" && globMatch(fs.name, channel):
+		return &redis.Message{Channel: channel, Pattern: fs.name}, true
+	case kind == "spublish" && fs.kind == "ssubscribe" && fs.name == channel:
+		return &redis.Message{Channel: channel}, true
+	default:
+		return nil, false
+	}
+}
+
+func (f *Fake) PublishJSON(ctx context.Context, channel string, value any) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	return f.Publish(ctx, channel, payload)
+}
+
+func (f *Fake) Publish(ctx context.Context, channel string, value any) error {
+	return f.publish(ctx, "publish", channel, value)
+}
+
+func (f *Fake) SPublish(ctx context.Context, channel string, value any) error {
+	return f.publish(ctx, "spublish", channel, value)
+}
+
+func (f *Fake) publish(ctx context.Context, kind, channel string, value any) error {
+	payload, err := fakeString(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", kind, err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var count int
+
+	for subscription := range f.subscriptions {
+		message, ok := subscription.match(kind, channel)
+		if !ok {
+			continue
+		}
+
+		count++
+		message.Payload = payload
+
+		select {
+		case subscription.output <- message:
+		default:
+			slog.LogAttrs(ctx, slog.LevelWarn, "fake subscription is full, message dropped", slog.String("channel", channel))
+		}
+	}
+
+	if count == 0 {
+		return ErrNoSubscriber
+	}
+
+	return nil
+}
+
+func (f *Fake) Subscribe(ctx context.Context, channel string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return f.subscribe(ctx, "subscribe", channel, options)
+}
+
+func (f *Fake) PSubscribe(ctx context.Context, pattern string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return f.subscribe(ctx, "psubscribe", pattern, options)
+}
+
+func (f *Fake) SSubscribe(ctx context.Context, channel string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return f.subscribe(ctx, "ssubscribe", channel, options)
+}
+
+func (f *Fake) subscribe(ctx context.Context, kind, name string, options []SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	subscription := &fakeSubscription{
+		ctx:    ctx,
+		output: make(chan *redis.Message, fakeChannelSize),
+		kind:   kind,
+		name:   name,
+	}
+
+	for _, option := range options {
+		option(&subscription.config)
+	}
+
+	f.mutex.Lock()
+	f.subscriptions[subscription] = struct{}{}
+	f.mutex.Unlock()
+
+	return subscription.output, func(_ context.Context) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		f.unsubscribe(subscription)
+	}
+}
+
+// unsubscribe closes the subscription, it's a no-op when already done. The mutex has to be held.
+func (f *Fake) unsubscribe(subscription *fakeSubscription) {
+	if _, ok := f.subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(f.subscriptions, subscription)
+	close(subscription.output)
+}
+
+// Reconnect simulates a connection loss, calling the OnReconnect hook of every subscription. Messages published meanwhile are lost for real, not with the fake.
+func (f *Fake) Reconnect() {
+	f.mutex.Lock()
+
+	var hooks []*fakeSubscription
+
+	for subscription := range f.subscriptions {
+		if subscription.config.onReconnect != nil {
+			hooks = append(hooks, subscription)
+		}
+	}
+
+	f.mutex.Unlock()
+
+	for _, subscription := range hooks {
+		subscription.config.onReconnect(subscription.ctx)
+	}
+}
@@ -0,0 +1,194 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sorted returns the members of the sorted set by ascending score, then lexicographically like Redis. The mutex has to be held.
+func (f *Fake) sorted(key string) ([]redis.Z, error) {
+	set, _, err := getAs[fakeZSet](f, key)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]redis.Z, 0, len(set))
+	for member, score := range set {
+		output = append(output, redis.Z{Score: score, Member: member})
+	}
+
+	slices.SortFunc(output, func(a, b redis.Z) int {
+		if order := cmp.Compare(a.Score, b.Score); order != 0 {
+			return order
+		}
+
+		return cmp.Compare(a.Member.(string), b.Member.(string))
+	})
+
+	return output, nil
+}
+
+// sortedSet returns the sorted set of the key, creating it if absent. The mutex has to be held.
+func (f *Fake) sortedSet(key string) (fakeZSet, error) {
+	set, ok, err := getAs[fakeZSet](f, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		set = make(fakeZSet)
+		f.set(key, set)
+	}
+
+	return set, nil
+}
+
+func (f *Fake) ZAdd(_ context.Context, key string, members ...redis.Z) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	set, err := f.sortedSet(key)
+	if err != nil {
+		return fmt.Errorf("zadd: %w", err)
+	}
+
+	for _, member := range members {
+		content, err := fakeString(member.Member)
+		if err != nil {
+			return fmt.Errorf("zadd: %w", err)
+		}
+
+		set[content] = member.Score
+	}
+
+	return nil
+}
+
+func (f *Fake) ZIncrBy(_ context.Context, key, member string, incr float64) (float64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	set, err := f.sortedSet(key)
+	if err != nil {
+		return 0, fmt.Errorf("zincrby: %w", err)
+	}
+
+	set[member] += incr
+
+	return set[member], nil
+}
+
+func (f *Fake) ZRange(_ context.Context, key string, start, stop int64, reverse bool) ([]redis.Z, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	members, err := f.sorted(key)
+	if err != nil {
+		return nil, fmt.Errorf("zrange: %w", err)
+	}
+
+	if reverse {
+		slices.Reverse(members)
+	}
+
+	length := int64(len(members))
+
+	// Negative ranks start from the end, like Redis.
+	if start < 0 {
+		start = max(length+start, 0)
+	}
+
+	if stop < 0 {
+		stop += length
+	}
+
+	stop = min(stop, length-1)
+
+	if start > stop {
+		return []redis.Z{}, nil
+	}
+
+	return members[start : stop+1], nil
+}
+
+func (f *Fake) ZRangeByScore(_ context.Context, key string, minScore, maxScore float64, offset, count int64) ([]redis.Z, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	members, err := f.sorted(key)
+	if err != nil {
+		return nil, fmt.Errorf("zrangebyscore: %w", err)
+	}
+
+	output := []redis.Z{}
+
+	for _, member := range members {
+		if member.Score < minScore || member.Score > maxScore {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+
+			continue
+		}
+
+		output = append(output, member)
+
+		if count > 0 && int64(len(output)) == count {
+			break
+		}
+	}
+
+	return output, nil
+}
+
+func (f *Fake) ZRank(_ context.Context, key, member string, reverse bool) (int64, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	members, err := f.sorted(key)
+	if err != nil {
+		return 0, false, fmt.Errorf("zrank: %w", err)
+	}
+
+	for index, item := range members {
+		if item.Member == member {
+			if reverse {
+				return int64(len(members) - 1 - index), true, nil
+			}
+
+			return int64(index), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func (f *Fake) ZRem(_ context.Context, key string, members ...string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	set, _, err := getAs[fakeZSet](f, key)
+	if err != nil {
+		return fmt.Errorf("zrem: %w", err)
+	}
+
+	for _, member := range members {
+		delete(set, member)
+	}
+
+	if set != nil && len(set) == 0 {
+		delete(f.entries, key)
+	}
+
+	return nil
+}
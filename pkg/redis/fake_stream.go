@@ -0,0 +1,266 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type fakeStream struct {
+	groups   map[string]*fakeGroup
+	messages []redis.XMessage
+	lastID   fakeStreamID
+}
+
+type fakeGroup struct {
+	pending       map[string]*fakePending
+	lastDelivered fakeStreamID
+}
+
+type fakePending struct {
+	deliveredAt time.Time
+	consumer    string
+}
+
+type fakeStreamID struct {
+	milliseconds int64
+	sequence     int64
+}
+
+func parseFakeStreamID(id string) fakeStreamID {
+	milliseconds, sequence, _ := strings.Cut(id, "-")
+
+	var output fakeStreamID
+	output.milliseconds, _ = strconv.ParseInt(milliseconds, 10, 64)
+	output.sequence, _ = strconv.ParseInt(sequence, 10, 64)
+
+	return output
+}
+
+func (id fakeStreamID) String() string {
+	return fmt.Sprintf("%d-%d", id.milliseconds, id.sequence)
+}
+
+func (id fakeStreamID) compare(other fakeStreamID) int {
+	if order := cmp.Compare(id.milliseconds, other.milliseconds); order != 0 {
+		return order
+	}
+
+	return cmp.Compare(id.sequence, other.sequence)
+}
+
+// stream returns the stream of the key, creating it if asked. The mutex has to be held.
+func (f *Fake) stream(key string, create bool) (*fakeStream, error) {
+	stream, ok, err := getAs[*fakeStream](f, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok && create {
+		stream = &fakeStream{groups: make(map[string]*fakeGroup)}
+		f.set(key, stream)
+	}
+
+	return stream, nil
+}
+
+// group returns the consumer group of the stream, or the NOGROUP error of Redis. The mutex has to be held.
+func (f *Fake) group(key, name string) (*fakeStream, *fakeGroup, error) {
+	stream, err := f.stream(key, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if stream != nil {
+		if group, ok := stream.groups[name]; ok {
+			return stream, group, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", key, name)
+}
+
+func (f *Fake) XAdd(_ context.Context, key string, maxLen int64, value any) (string, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	stream, err := f.stream(key, true)
+	if err != nil {
+		return "", fmt.Errorf("xadd: %w", err)
+	}
+
+	id := fakeStreamID{milliseconds: f.clock().UnixMilli()}
+	if id.compare(stream.lastID) <= 0 {
+		id = fakeStreamID{milliseconds: stream.lastID.milliseconds, sequence: stream.lastID.sequence + 1}
+	}
+
+	stream.lastID = id
+	stream.messages = append(stream.messages, redis.XMessage{
+		ID:     id.String(),
+		Values: map[string]any{streamPayloadField: string(payload)},
+	})
+
+	if maxLen > 0 && int64(len(stream.messages)) > maxLen {
+		stream.messages = slices.Clone(stream.messages[int64(len(stream.messages))-maxLen:])
+	}
+
+	f.notify()
+
+	return id.String(), nil
+}
+
+func (f *Fake) XGroup(_ context.Context, key, name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	stream, err := f.stream(key, true)
+	if err != nil {
+		return fmt.Errorf("xgroup create: %w", err)
+	}
+
+	if _, ok := stream.groups[name]; !ok {
+		stream.groups[name] = &fakeGroup{
+			pending:       make(map[string]*fakePending),
+			lastDelivered: stream.lastID,
+		}
+	}
+
+	return nil
+}
+
+// XReadGroup waits at most block for a new message, forever when zero like Redis.
+func (f *Fake) XReadGroup(ctx context.Context, key, group, consumer string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	var timeout <-chan time.Time
+
+	if block > 0 {
+		timer := time.NewTimer(block)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	for {
+		f.mutex.Lock()
+		messages, err := f.readGroup(key, group, consumer, count)
+		changed := f.changed
+		f.mutex.Unlock()
+
+		if err != nil {
+			return nil, fmt.Errorf("xreadgroup: %w", err)
+		}
+
+		if len(messages) != 0 || block < 0 {
+			return messages, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("xreadgroup: %w", ctx.Err())
+		case <-timeout:
+			return nil, nil
+		case <-changed:
+		}
+	}
+}
+
+// readGroup delivers the messages after the last one delivered to the group. The mutex has to be held.
+func (f *Fake) readGroup(key, name, consumer string, count int64) ([]redis.XMessage, error) {
+	stream, group, err := f.group(key, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []redis.XMessage
+
+	for _, message := range stream.messages {
+		id := parseFakeStreamID(message.ID)
+		if id.compare(group.lastDelivered) <= 0 {
+			continue
+		}
+
+		group.lastDelivered = id
+		group.pending[message.ID] = &fakePending{consumer: consumer, deliveredAt: f.clock()}
+		output = append(output, message)
+
+		if count > 0 && int64(len(output)) == count {
+			break
+		}
+	}
+
+	return output, nil
+}
+
+func (f *Fake) XAutoClaim(_ context.Context, key, name, consumer string, minIdle time.Duration, count int64) ([]redis.XMessage, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	stream, group, err := f.group(key, name)
+	if err != nil {
+		return nil, fmt.Errorf("xautoclaim: %w", err)
+	}
+
+	ids := slices.SortedFunc(maps.Keys(group.pending), func(a, b string) int {
+		return parseFakeStreamID(a).compare(parseFakeStreamID(b))
+	})
+
+	var output []redis.XMessage
+
+	now := f.clock()
+
+	for _, id := range ids {
+		pending := group.pending[id]
+		if now.Sub(pending.deliveredAt) < minIdle {
+			continue
+		}
+
+		index := slices.IndexFunc(stream.messages, func(message redis.XMessage) bool {
+			return message.ID == id
+		})
+
+		// The message has been trimmed meanwhile.
+		if index < 0 {
+			delete(group.pending, id)
+
+			continue
+		}
+
+		pending.consumer = consumer
+		pending.deliveredAt = now
+		output = append(output, stream.messages[index])
+
+		if count > 0 && int64(len(output)) == count {
+			break
+		}
+	}
+
+	return output, nil
+}
+
+func (f *Fake) XAck(_ context.Context, key, name string, ids ...string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, group, err := f.group(key, name)
+	if err != nil {
+		return fmt.Errorf("xack: %w", err)
+	}
+
+	for _, id := range ids {
+		delete(group.pending, id)
+	}
+
+	return nil
+}
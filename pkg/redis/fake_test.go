@@ -0,0 +1,392 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeExpiration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := NewFake()
+
+	assert.NoError(t, fake.StoreMany(ctx, map[string]any{"first": "1", "second": 2}, time.Minute))
+	assert.NoError(t, fake.Store(ctx, "forever", []byte("value"), 0))
+
+	got, err := fake.LoadMany(ctx, "first", "unknown", "second")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "", "2"}, got)
+
+	ttl, ok, err := fake.TTL(ctx, "first")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, ttl)
+
+	ttl, ok, err = fake.TTL(ctx, "forever")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	fake.Advance(time.Minute)
+
+	content, err := fake.Load(ctx, "first")
+	assert.NoError(t, err)
+	assert.Nil(t, content)
+
+	_, ok, err = fake.TTL(ctx, "second")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	content, err = fake.Load(ctx, "forever")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), content)
+
+	assert.NoError(t, fake.Expire(ctx, 0, "forever"))
+
+	content, err = fake.Load(ctx, "forever")
+	assert.NoError(t, err)
+	assert.Nil(t, content)
+}
+
+func TestFakeScan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := NewFake()
+
+	assert.NoError(t, fake.StoreMany(ctx, map[string]any{"user:1": "", "user:2": "", "user:10": "", "item:1": ""}, 0))
+
+	output := make(chan string, 4)
+	assert.NoError(t, fake.Scan(ctx, "user:?", output, 10))
+
+	var keys []string
+	for key := range output {
+		keys = append(keys, key)
+	}
+
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+
+	assert.NoError(t, fake.DeletePattern(ctx, "user:*"))
+
+	got, err := fake.LoadMany(ctx, "user:10", "item:1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", ""}, got)
+	assert.Len(t, fake.keys("*"), 1)
+}
+
+func TestFakeTag(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := NewFake()
+
+	assert.NoError(t, fake.StoreMany(ctx, map[string]any{"1": "a", "2": "b", "3": "c"}, 0))
+	assert.NoError(t, fake.Tag(ctx, map[string][]string{"tag:odd": {"1"}}, time.Minute))
+	assert.NoError(t, fake.Tag(ctx, map[string][]string{"tag:odd": {"3"}}, time.Second))
+
+	ttl, ok, err := fake.TTL(ctx, "tag:odd")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, ttl)
+
+	fake.Advance(time.Second * 2)
+
+	_, err = fake.Load(ctx, "tag:odd")
+	assert.ErrorIs(t, err, errWrongType)
+
+	assert.NoError(t, fake.Tag(ctx, map[string][]string{"tag:odd": {"5"}}, time.Minute))
+
+	members, _, err := getAs[fakeZSet](fake, "tag:odd")
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+	assert.Contains(t, members, "1")
+	assert.Contains(t, members, "5")
+
+	assert.NoError(t, fake.Tag(ctx, map[string][]string{"tag:odd": {"7"}}, 0))
+	assert.NoError(t, fake.Tag(ctx, map[string][]string{"tag:odd": {"9"}}, time.Second))
+
+	ttl, ok, err = fake.TTL(ctx, "tag:odd")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	assert.NoError(t, fake.DeleteTag(ctx, "tag:odd"))
+	assert.Equal(t, []string{"2", "3"}, fake.keys("*"))
+}
+
+func TestFakePubSub(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := NewFake()
+
+	assert.ErrorIs(t, fake.Publish(ctx, "events:users", "created"), ErrNoSubscriber)
+
+	var reconnected bool
+
+	exact, unsubscribeExact := fake.Subscribe(ctx, "events:users", OnReconnect(func(context.Context) { reconnected = true }))
+	pattern, unsubscribePattern := fake.PSubscribe(ctx, "events:*")
+	shard, unsubscribeShard := fake.SSubscribe(ctx, "events:users")
+
+	assert.NoError(t, fake.PublishJSON(ctx, "events:users", "created"))
+	assert.Equal(t, `"created"`, (<-exact).Payload)
+
+	message := <-pattern
+	assert.Equal(t, "events:users", message.Channel)
+	assert.Equal(t, "events:*", message.Pattern)
+	assert.Empty(t, shard)
+
+	assert.NoError(t, fake.SPublish(ctx, "events:users", "paid"))
+	assert.Equal(t, "paid", (<-shard).Payload)
+
+	fake.Reconnect()
+	assert.True(t, reconnected)
+
+	unsubscribeExact(ctx)
+	unsubscribePattern(ctx)
+	unsubscribeShard(ctx)
+
+	_, ok := <-exact
+	assert.False(t, ok)
+	assert.ErrorIs(t, fake.Publish(ctx, "events:users", "deleted"), ErrNoSubscriber)
+}
+
+func TestFakePull(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	fake := NewFake()
+
+	assert.NoError(t, fake.Push(ctx, "jobs", 1))
+
+	var got []int
+
+	go func() {
+		for id := 2; id <= 3; id++ {
+			assert.NoError(t, fake.Push(ctx, "jobs", id))
+		}
+	}()
+
+	PullFor(ctx, fake, "jobs", func(id int, err error) {
+		assert.NoError(t, err)
+
+		if got = append(got, id); len(got) == 3 {
+			cancel()
+		}
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, got)
+}
+
+func TestFakePullReliable(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	fake := NewFake()
+
+	fake.PullReliable(ctx, "jobs", ReliableConfig{Consumer: "test"}, func(_ context.Context, _ Delivery, err error) {
+		assert.ErrorContains(t, err, "invalid visibility timeout")
+	})
+
+	assert.NoError(t, fake.Push(ctx, "jobs", "failing"))
+	assert.NoError(t, fake.Push(ctx, "jobs", "failing"))
+	assert.NoError(t, fake.Push(ctx, "jobs", "working"))
+
+	var retries []int
+
+	fake.PullReliable(ctx, "jobs", ReliableConfig{Consumer: "test", VisibilityTimeout: time.Minute, MaxRetry: 2}, func(ctx context.Context, delivery Delivery, err error) {
+		assert.NoError(t, err)
+
+		if delivery.Payload == `"working"` {
+			assert.NoError(t, delivery.Ack(ctx))
+
+			return
+		}
+
+		retries = append(retries, delivery.Retry)
+		assert.NoError(t, delivery.Nack(ctx))
+
+		if len(retries) == 6 {
+			cancel()
+		}
+	})
+
+	assert.ElementsMatch(t, []int{0, 0, 1, 1, 2, 2}, retries)
+	assert.Equal(t, []string{DeadLetterKey("jobs")}, fake.keys("*"))
+
+	deadLetters, _, err := getAs[fakeList](fake, DeadLetterKey("jobs"))
+	assert.NoError(t, err)
+	assert.Equal(t, fakeList{`"failing"`, `"failing"`}, deadLetters)
+}
+
+func TestFakeExclusive(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := NewFake()
+
+	acquired, err := fake.Exclusive(ctx, "job", time.Minute, func(ctx context.Context) error {
+		acquired, err := fake.Exclusive(ctx, "job", time.Minute, func(context.Context) error {
+			return errors.New("should not run")
+		})
+
+		assert.False(t, acquired)
+
+		return err
+	})
+
+	assert.True(t, acquired)
+	assert.NoError(t, err)
+
+	var fencings []int64
+
+	for range 2 {
+		_, err = fake.Lock(ctx, "job", time.Minute, func(_ context.Context, fencing int64) error {
+			fencings = append(fencings, fencing)
+
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []int64{1, 2}, fencings)
+	assert.Equal(t, []string{"{job}:fencing"}, fake.keys("*"))
+}
+
+func TestFakeLockLost(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFake()
+
+	acquired, err := fake.Lock(context.Background(), "job", time.Millisecond*30, func(ctx context.Context, _ int64) error {
+		fake.Advance(time.Second)
+
+		<-ctx.Done()
+
+		return context.Cause(ctx)
+	})
+
+	assert.True(t, acquired)
+	assert.ErrorIs(t, err, ErrLockLost)
+}
+
+func TestFakeCollections(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := NewFake()
+
+	counters := NewHashFor[int64](fake, "counters")
+
+	value, err := counters.Incr(ctx, "views", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), value)
+
+	all, err := counters.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"views": 3}, all)
+
+	leaderboard := NewSortedSetFor[string](fake, "leaderboard")
+
+	assert.NoError(t, leaderboard.Add(ctx, Scored[string]{Value: "alice", Score: 10}, Scored[string]{Value: "bob", Score: 20}))
+
+	_, err = leaderboard.Incr(ctx, "carol", 30)
+	assert.NoError(t, err)
+
+	rank, ok, err := leaderboard.Rank(ctx, "carol", true)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), rank)
+
+	members, err := leaderboard.RangeByScore(ctx, 15, math.Inf(1), 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []Scored[string]{{Value: "carol", Score: 30}}, members)
+
+	page, cursor, err := leaderboard.Page(ctx, 2, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []Scored[string]{{Value: "alice", Score: 10}}, page)
+	assert.Equal(t, uint64(0), cursor)
+
+	_, err = NewHashFor[int64](fake, "leaderboard").Incr(ctx, "views", 1)
+	assert.ErrorIs(t, err, errWrongType)
+}
+
+func TestFakeStream(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := NewFake()
+
+	assert.NoError(t, fake.XGroup(ctx, "events", "workers"))
+
+	for id := range 3 {
+		_, err := fake.XAdd(ctx, "events", 100, map[string]int{"id": id})
+		assert.NoError(t, err)
+	}
+
+	messages, err := fake.XReadGroup(ctx, "events", "workers", "dead", 10, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 3)
+
+	assert.NoError(t, fake.XAck(ctx, "events", "workers", messages[0].ID))
+
+	messages, err = fake.XReadGroup(ctx, "events", "workers", "dead", 10, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+
+	fake.Advance(time.Minute)
+
+	claimed, err := fake.XAutoClaim(ctx, "events", "workers", "alive", time.Minute, 10)
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 2)
+
+	var value struct {
+		ID int `json:"id"`
+	}
+
+	assert.NoError(t, decodeStreamMessage(claimed[0], &value))
+	assert.Equal(t, 1, value.ID)
+
+	_, err = fake.XReadGroup(ctx, "events", "unknown", "alive", 10, time.Millisecond)
+	assert.ErrorContains(t, err, "NOGROUP")
+}
+
+func TestGlobMatch(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		"exact":          {"user", "user", true},
+		"star":           {"user:*", "user:1:name", true},
+		"star empty":     {"user:*", "user:", true},
+		"star middle":    {"user:*:name", "user:1:email", false},
+		"question":       {"user:?", "user:10", false},
+		"class":          {"user:[0-9]", "user:5", true},
+		"negated class":  {"user:[^0-9]", "user:5", false},
+		"class letters":  {"h[ae]llo", "hillo", false},
+		"escaped":        {`user\*`, "user*", true},
+		"escaped star":   {`user\*`, "user1", false},
+		"unclosed class": {"user[", "user[", true},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.want, globMatch(testCase.pattern, testCase.name))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Hasher is the subset of Client used for manipulating the fields of a hash.
+type Hasher interface {
+	HGet(ctx context.Context, key, field string) ([]byte, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HSet(ctx context.Context, key string, values map[string]any) error
+	HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error)
+	HDel(ctx context.Context, key string, fields ...string) error
+}
+
+// HGet returns the value of the field, nil if absent.
+func (s *Service) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	content, err := s.client.HGet(ctx, key, field).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("hget: %w", err)
+	}
+
+	return content, nil
+}
+
+func (s *Service) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	values, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("hgetall: %w", err)
+	}
+
+	return values, nil
+}
+
+func (s *Service) HSet(ctx context.Context, key string, values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if err := s.client.HSet(ctx, key, values).Err(); err != nil {
+		return fmt.Errorf("hset: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	value, err := s.client.HIncrBy(ctx, key, field, incr).Result()
+	if err != nil {
+		return 0, fmt.Errorf("hincrby: %w", err)
+	}
+
+	return value, nil
+}
+
+func (s *Service) HDel(ctx context.Context, key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if err := s.client.HDel(ctx, key, fields...).Err(); err != nil {
+		return fmt.Errorf("hdel: %w", err)
+	}
+
+	return nil
+}
+
+// HashFor manipulates the fields of a hash holding JSON values.
+type HashFor[T any] struct {
+	client Hasher
+	key    string
+}
+
+func NewHashFor[T any](client Hasher, key string) HashFor[T] {
+	return HashFor[T]{
+		client: client,
+		key:    key,
+	}
+}
+
+// Get returns the value of the field, and false if it's absent.
+func (h HashFor[T]) Get(ctx context.Context, field string) (T, bool, error) {
+	var output T
+
+	content, err := h.client.HGet(ctx, h.key, field)
+	if err != nil || content == nil {
+		return output, false, err
+	}
+
+	if err := json.Unmarshal(content, &output); err != nil {
+		return output, false, fmt.Errorf("unmarshal `%s`: %w", field, err)
+	}
+
+	return output, true, nil
+}
+
+func (h HashFor[T]) GetAll(ctx context.Context) (map[string]T, error) {
+	values, err := h.client.HGetAll(ctx, h.key)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make(map[string]T, len(values))
+
+	for field, content := range values {
+		var value T
+
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			return nil, fmt.Errorf("unmarshal `%s`: %w", field, err)
+		}
+
+		output[field] = value
+	}
+
+	return output, nil
+}
+
+func (h HashFor[T]) Set(ctx context.Context, field string, value T) error {
+	return h.SetMany(ctx, map[string]T{field: value})
+}
+
+func (h HashFor[T]) SetMany(ctx context.Context, values map[string]T) error {
+	payloads := make(map[string]any, len(values))
+
+	for field, value := range values {
+		payload, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("marshal `%s`: %w", field, err)
+		}
+
+		payloads[field] = payload
+	}
+
+	return h.client.HSet(ctx, h.key, payloads)
+}
+
+// Incr increments the field by incr and returns its new value, the field has to hold an integer, e.g. a HashFor[int64].
+func (h HashFor[T]) Incr(ctx context.Context, field string, incr int64) (int64, error) {
+	return h.client.HIncrBy(ctx, h.key, field, incr)
+}
+
+func (h HashFor[T]) Delete(ctx context.Context, fields ...string) error {
+	return h.client.HDel(ctx, h.key, fields...)
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeHasher map[string]string
+
+func (fh fakeHasher) HGet(_ context.Context, _, field string) ([]byte, error) {
+	if value, ok := fh[field]; ok {
+		return []byte(value), nil
+	}
+
+	return nil, nil
+}
+
+func (fh fakeHasher) HGetAll(_ context.Context, _ string) (map[string]string, error) {
+	return fh, nil
+}
+
+func (fh fakeHasher) HSet(_ context.Context, _ string, values map[string]any) error {
+	for field, value := range values {
+		fh[field] = string(value.([]byte))
+	}
+
+	return nil
+}
+
+func (fh fakeHasher) HIncrBy(_ context.Context, _, _ string, incr int64) (int64, error) {
+	return incr, nil
+}
+
+func (fh fakeHasher) HDel(_ context.Context, _ string, fields ...string) error {
+	for _, field := range fields {
+		delete(fh, field)
+	}
+
+	return nil
+}
+
+type profile struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestHashFor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	hasher := fakeHasher{}
+	hash := NewHashFor[profile](hasher, "profiles")
+
+	assert.NoError(t, hash.Set(ctx, "bob", profile{Name: "Bob", Age: 42}))
+	assert.Equal(t, `{"name":"Bob","age":42}`, hasher["bob"])
+
+	got, ok, err := hash.Get(ctx, "bob")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, profile{Name: "Bob", Age: 42}, got)
+
+	_, ok, err = hash.Get(ctx, "alice")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	hasher["invalid"] = "{"
+
+	_, _, err = hash.Get(ctx, "invalid")
+	assert.ErrorContains(t, err, "unmarshal `invalid`")
+
+	_, err = hash.GetAll(ctx)
+	assert.ErrorContains(t, err, "unmarshal `invalid`")
+
+	assert.NoError(t, hash.Delete(ctx, "invalid"))
+
+	all, err := hash.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]profile{"bob": {Name: "Bob", Age: 42}}, all)
+}
@@ -0,0 +1,219 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockLost is the cause of the action's context cancellation when the lease can't be renewed.
+var ErrLockLost = errors.New("lock lost")
+
+// fencedAcquireScript sets the lock with the owner token and increments the fencing counter, that never expires. It returns 0 when the lock is already held.
+var fencedAcquireScript = redis.NewScript(`
+if not redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+  return 0
+end
+
+return redis.call("INCR", KEYS[2])
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+
+return 0
+`)
+
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+
+return 0
+`)
+
+type lease struct {
+	name    string
+	fencing string
+	token   string
+}
+
+// newLease uses the lock name as hash tag of the fencing counter, so both live on the same cluster slot. Only a fenced lease creates the counter.
+func newLease(name string, fenced bool) lease {
+	lock := lease{
+		name:  name,
+		token: rand.Text(),
+	}
+
+	if fenced {
+		lock.fencing = fmt.Sprintf("{%s}:fencing", name)
+	}
+
+	return lock
+}
+
+// acquire sets the lock, returning its fencing token for a fenced lease, 1 otherwise, or 0 when the lock is already held.
+func (s *Service) acquire(ctx context.Context, lock lease, ttl time.Duration) (int64, error) {
+	if len(lock.fencing) == 0 {
+		acquired, err := s.client.SetNX(ctx, lock.name, lock.token, ttl).Result()
+		if err != nil {
+			return 0, fmt.Errorf("setnx: %w", err)
+		}
+
+		if acquired {
+			return 1, nil
+		}
+
+		return 0, nil
+	}
+
+	fencing, err := fencedAcquireScript.Run(ctx, s.client, []string{lock.name, lock.fencing}, lock.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("exec acquire script: %w", err)
+	}
+
+	return fencing, nil
+}
+
+func (s *Service) release(ctx context.Context, lock lease) error {
+	if err := releaseScript.Run(ctx, s.client, []string{lock.name}, lock.token).Err(); err != nil {
+		return fmt.Errorf("exec release script: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) extend(ctx context.Context, lock lease, ttl time.Duration) (bool, error) {
+	extended, err := extendScript.Run(ctx, s.client, []string{lock.name}, lock.token, ttl.Milliseconds()).Bool()
+	if err != nil {
+		return false, fmt.Errorf("exec extend script: %w", err)
+	}
+
+	return extended, nil
+}
+
+// leaser stores the leases of the locks.
+type leaser interface {
+	acquire(ctx context.Context, lock lease, ttl time.Duration) (int64, error)
+	release(ctx context.Context, lock lease) error
+	extend(ctx context.Context, lock lease, ttl time.Duration) (bool, error)
+}
+
+// Exclusive runs the action if the lock is acquired, during at most the timeout. The lock is released only if it's still owned, so a slow action can't release the lock of another holder.
+func (s *Service) Exclusive(ctx context.Context, name string, timeout time.Duration, action func(context.Context) error) (bool, error) {
+	return exclusive(ctx, s, name, timeout, action)
+}
+
+// Lock runs the action if the lock is acquired, renewing the lease every third of the ttl until the action returns. The action receives a fencing token, greater than the one of every previous holder, to reject writes of a holder that lost the lock. The action's context is cancelled with ErrLockLost if the lease can't be renewed.
+func (s *Service) Lock(ctx context.Context, name string, ttl time.Duration, action func(ctx context.Context, fencing int64) error) (bool, error) {
+	return lockWith(ctx, s, name, ttl, action)
+}
+
+func exclusive(ctx context.Context, store leaser, name string, timeout time.Duration, action func(context.Context) error) (bool, error) {
+	lock := newLease(name, false)
+
+	fencing, err := store.acquire(ctx, lock, timeout)
+	if err != nil {
+		return false, err
+	}
+
+	if fencing == 0 {
+		return false, nil
+	}
+
+	actionCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = action(actionCtx)
+
+	if releaseErr := store.release(context.WithoutCancel(ctx), lock); releaseErr != nil {
+		err = errors.Join(err, releaseErr)
+	}
+
+	return true, err
+}
+
+func lockWith(ctx context.Context, store leaser, name string, ttl time.Duration, action func(ctx context.Context, fencing int64) error) (bool, error) {
+	lock := newLease(name, true)
+
+	fencing, err := store.acquire(ctx, lock, ttl)
+	if err != nil {
+		return false, err
+	}
+
+	if fencing == 0 {
+		return false, nil
+	}
+
+	actionCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	renewDone := make(chan struct{})
+
+	go func() {
+		defer close(renewDone)
+
+		renew(actionCtx, store, lock, ttl, cancel)
+	}()
+
+	err = action(actionCtx, fencing)
+
+	cancel(nil)
+	<-renewDone
+
+	if releaseErr := store.release(context.WithoutCancel(ctx), lock); releaseErr != nil {
+		err = errors.Join(err, releaseErr)
+	}
+
+	return true, err
+}
+
+// renew extends the lease until the context is done. Transient errors are retried until the ttl has elapsed since the last renewal, the lease being expired by then.
+func renew(ctx context.Context, store leaser, lock lease, ttl time.Duration, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(max(ttl/3, time.Millisecond))
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		attemptAt := time.Now()
+
+		extended, err := store.extend(ctx, lock, ttl)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			slog.LogAttrs(ctx, slog.LevelWarn, "renew lock", slog.String("name", lock.name), slog.Any("error", err))
+
+			if time.Since(renewedAt) >= ttl {
+				cancel(ErrLockLost)
+
+				return
+			}
+
+			continue
+		}
+
+		if !extended {
+			cancel(ErrLockLost)
+
+			return
+		}
+
+		renewedAt = attemptAt
+	}
+}
@@ -0,0 +1,74 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+	"github.com/ViBiOh/httputils/v4/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	integration := test.NewRedisIntegration(t)
+	integration.Bootstrap(context.Background(), "redis_lock")
+
+	defer integration.Close(context.Background())
+	defer integration.Reset()
+
+	client := integration.Client()
+	ctx := context.Background()
+
+	var fencings []int64
+
+	acquired, err := client.Lock(ctx, "job", time.Millisecond*300, func(ctx context.Context, fencing int64) error {
+		fencings = append(fencings, fencing)
+
+		concurrent, err := client.Lock(ctx, "job", time.Second, func(_ context.Context, _ int64) error {
+			return nil
+		})
+		assert.False(t, concurrent)
+		assert.NoError(t, err)
+
+		// Longer than the ttl, the lease is renewed meanwhile.
+		time.Sleep(time.Millisecond * 500)
+
+		return ctx.Err()
+	})
+	assert.True(t, acquired)
+	assert.NoError(t, err)
+
+	acquired, err = client.Lock(ctx, "job", time.Second, func(_ context.Context, fencing int64) error {
+		fencings = append(fencings, fencing)
+
+		return nil
+	})
+	assert.True(t, acquired)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, fencings)
+
+	acquired, err = client.Lock(ctx, "lost", time.Millisecond*300, func(ctx context.Context, _ int64) error {
+		assert.NoError(t, client.Delete(ctx, "lost"))
+
+		<-ctx.Done()
+
+		return context.Cause(ctx)
+	})
+	assert.True(t, acquired)
+	assert.ErrorIs(t, err, redis.ErrLockLost)
+
+	acquired, err = client.Exclusive(ctx, "plain", time.Second, func(context.Context) error {
+		return nil
+	})
+	assert.True(t, acquired)
+	assert.NoError(t, err)
+
+	fencing, err := client.Load(ctx, "{plain}:fencing")
+	assert.NoError(t, err)
+	assert.Nil(t, fencing)
+}
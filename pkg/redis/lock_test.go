@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingLeaser struct{}
+
+func (failingLeaser) acquire(context.Context, lease, time.Duration) (int64, error) {
+	return 1, nil
+}
+
+func (failingLeaser) release(context.Context, lease) error {
+	return nil
+}
+
+func (failingLeaser) extend(context.Context, lease, time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestRenew(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		renew(ctx, failingLeaser{}, newLease("job", true), time.Millisecond*30, cancel)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("renew still retrying after the ttl")
+	}
+
+	assert.ErrorIs(t, context.Cause(ctx), ErrLockLost)
+}
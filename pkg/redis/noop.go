@@ -62,10 +62,26 @@ func (n Noop) Exclusive(_ context.Context, _ string, _ time.Duration, _ func(con
 	return false, ErrDisabled
 }
 
+func (n Noop) Lock(_ context.Context, _ string, _ time.Duration, _ func(context.Context, int64) error) (bool, error) {
+	return false, ErrDisabled
+}
+
 func (n Noop) Expire(_ context.Context, _ time.Duration, _ ...string) error {
 	return nil
 }
 
+func (n Noop) TTL(_ context.Context, _ string) (time.Duration, bool, error) {
+	return 0, false, nil
+}
+
+func (n Noop) Tag(_ context.Context, _ map[string][]string, _ time.Duration) error {
+	return nil
+}
+
+func (n Noop) DeleteTag(_ context.Context, _ string) error {
+	return nil
+}
+
 func (n Noop) Push(_ context.Context, _ string, _ any) error {
 	return nil
 }
@@ -74,6 +90,10 @@ func (n Noop) Pull(_ context.Context, _ string, _ func(string, error)) {
 	// noop
 }
 
+func (n Noop) PullReliable(_ context.Context, _ string, _ ReliableConfig, _ func(context.Context, Delivery, error)) {
+	// noop
+}
+
 func (n Noop) Publish(_ context.Context, _ string, _ any) error {
 	return nil
 }
@@ -82,13 +102,102 @@ func (n Noop) PublishJSON(_ context.Context, _ string, _ any) error {
 	return nil
 }
 
-func (n Noop) Subscribe(_ context.Context, _ string) (<-chan *redis.Message, func(context.Context)) {
+func (n Noop) SPublish(_ context.Context, _ string, _ any) error {
+	return nil
+}
+
+func (n Noop) Subscribe(_ context.Context, _ string, _ ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return closedSubscription()
+}
+
+func (n Noop) PSubscribe(_ context.Context, _ string, _ ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return closedSubscription()
+}
+
+func (n Noop) SSubscribe(_ context.Context, _ string, _ ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return closedSubscription()
+}
+
+func closedSubscription() (<-chan *redis.Message, func(context.Context)) {
 	content := make(chan *redis.Message, 1)
 	close(content)
 
 	return content, func(_ context.Context) {}
 }
 
+func (n Noop) RunScript(_ context.Context, _ *redis.Script, _ []string, _ ...any) (any, error) {
+	return nil, ErrDisabled
+}
+
 func (n Noop) Pipeline() redis.Pipeliner {
 	return nil
 }
+
+func (n Noop) XAdd(_ context.Context, _ string, _ int64, _ any) (string, error) {
+	return "", nil
+}
+
+func (n Noop) XGroup(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (n Noop) XReadGroup(ctx context.Context, _, _, _ string, _ int64, block time.Duration) ([]redis.XMessage, error) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(block):
+	}
+
+	return nil, nil
+}
+
+func (n Noop) XAutoClaim(_ context.Context, _, _, _ string, _ time.Duration, _ int64) ([]redis.XMessage, error) {
+	return nil, nil
+}
+
+func (n Noop) XAck(_ context.Context, _, _ string, _ ...string) error {
+	return nil
+}
+
+func (n Noop) HGet(_ context.Context, _, _ string) ([]byte, error) {
+	return nil, nil
+}
+
+func (n Noop) HGetAll(_ context.Context, _ string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (n Noop) HSet(_ context.Context, _ string, _ map[string]any) error {
+	return nil
+}
+
+func (n Noop) HIncrBy(_ context.Context, _, _ string, _ int64) (int64, error) {
+	return 0, ErrDisabled
+}
+
+func (n Noop) HDel(_ context.Context, _ string, _ ...string) error {
+	return nil
+}
+
+func (n Noop) ZAdd(_ context.Context, _ string, _ ...redis.Z) error {
+	return nil
+}
+
+func (n Noop) ZIncrBy(_ context.Context, _, _ string, _ float64) (float64, error) {
+	return 0, ErrDisabled
+}
+
+func (n Noop) ZRange(_ context.Context, _ string, _, _ int64, _ bool) ([]redis.Z, error) {
+	return nil, nil
+}
+
+func (n Noop) ZRangeByScore(_ context.Context, _ string, _, _ float64, _, _ int64) ([]redis.Z, error) {
+	return nil, nil
+}
+
+func (n Noop) ZRank(_ context.Context, _, _ string, _ bool) (int64, bool, error) {
+	return 0, false, nil
+}
+
+func (n Noop) ZRem(_ context.Context, _ string, _ ...string) error {
+	return nil
+}
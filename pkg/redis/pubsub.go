@@ -6,11 +6,25 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/ViBiOh/httputils/v4/pkg/concurrent"
 	"github.com/redis/go-redis/v9"
 )
 
+type subscribeConfig struct {
+	onReconnect func(context.Context)
+}
+
+type SubscribeOption func(*subscribeConfig)
+
+// OnReconnect calls the hook when the subscription is restored after a connection loss, messages published in between being lost.
+func OnReconnect(hook func(context.Context)) SubscribeOption {
+	return func(s *subscribeConfig) {
+		s.onReconnect = hook
+	}
+}
+
 func (s *Service) PublishJSON(ctx context.Context, channel string, value any) error {
 	payload, err := json.Marshal(value)
 	if err != nil {
@@ -33,26 +47,145 @@ func (s *Service) Publish(ctx context.Context, channel string, value any) (err e
 	return nil
 }
 
-func (s *Service) Subscribe(ctx context.Context, channel string) (<-chan *redis.Message, func(context.Context)) {
+// SPublish publishes to a sharded channel, only the nodes of its slot receive the message in a cluster.
+func (s *Service) SPublish(ctx context.Context, channel string, value any) error {
+	count, err := s.client.SPublish(ctx, channel, value).Result()
+	if err != nil {
+		return fmt.Errorf("spublish: %w", err)
+	}
+
+	if count == 0 {
+		return ErrNoSubscriber
+	}
+
+	return nil
+}
+
+func (s *Service) Subscribe(ctx context.Context, channel string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
 	pubsub := s.client.Subscribe(ctx, channel)
 
-	return pubsub.Channel(), func(ctx context.Context) {
-		if err := pubsub.Unsubscribe(ctx, channel); err != nil && !errors.Is(err, redis.ErrClosed) {
-			slog.LogAttrs(ctx, slog.LevelError, "unsubscribe pubsub", slog.String("channel", channel), slog.Any("error", err))
-		}
+	return listen(ctx, pubsub, channel, options, pubsub.Unsubscribe)
+}
+
+// PSubscribe subscribes to the channels matching the glob-style pattern, e.g. `events:*`.
+func (s *Service) PSubscribe(ctx context.Context, pattern string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	pubsub := s.client.PSubscribe(ctx, pattern)
+
+	return listen(ctx, pubsub, pattern, options, pubsub.PUnsubscribe)
+}
+
+// SSubscribe subscribes to the sharded channel, it's served by the nodes of its slot in a cluster instead of being broadcasted to every node.
+func (s *Service) SSubscribe(ctx context.Context, channel string, options ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	pubsub := s.client.SSubscribe(ctx, channel)
+
+	return listen(ctx, pubsub, channel, options, pubsub.SUnsubscribe)
+}
+
+// listen forwards the messages of the pubsub, the returned unsubscribe being safe to call more than once. The client resubscribes by itself after a connection loss, so every subscription confirmation after the first one is a reconnection.
+func listen(ctx context.Context, pubsub *redis.PubSub, channel string, options []SubscribeOption, unsubscribe func(context.Context, ...string) error) (<-chan *redis.Message, func(context.Context)) {
+	var config subscribeConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	output := make(chan *redis.Message)
+	done := make(chan struct{})
 
-		if err := pubsub.Close(); err != nil {
-			slog.LogAttrs(ctx, slog.LevelError, "close pubsub", slog.String("channel", channel), slog.Any("error", err))
+	var once sync.Once
+
+	go func() {
+		defer close(output)
+
+		var subscribed bool
+
+		for item := range pubsub.ChannelWithSubscriptions() {
+			switch message := item.(type) {
+			case *redis.Subscription:
+				if !isSubscription(message.Kind) {
+					continue
+				}
+
+				if subscribed {
+					slog.LogAttrs(ctx, slog.LevelWarn, "pubsub reconnected", slog.String("channel", channel))
+
+					if config.onReconnect != nil {
+						config.onReconnect(ctx)
+					}
+				}
+
+				subscribed = true
+
+			case *redis.Message:
+				select {
+				case output <- message:
+				case <-done:
+					return
+				}
+			}
 		}
+	}()
+
+	return output, func(ctx context.Context) {
+		once.Do(func() {
+			close(done)
+
+			if err := unsubscribe(ctx, channel); err != nil && !errors.Is(err, redis.ErrClosed) {
+				slog.LogAttrs(ctx, slog.LevelError, "unsubscribe pubsub", slog.String("channel", channel), slog.Any("error", err))
+			}
+
+			if err := pubsub.Close(); err != nil {
+				slog.LogAttrs(ctx, slog.LevelError, "close pubsub", slog.String("channel", channel), slog.Any("error", err))
+			}
+		})
 	}
 }
 
-func SubscribeFor[T any](ctx context.Context, client Subscriber, channel string, handler func(T, error)) {
-	subscription, unsubscribe := client.Subscribe(ctx, channel)
+func isSubscription(kind string) bool {
+	switch kind {
+	case "subscribe", "psubscribe", "ssubscribe":
+		return true
+	default:
+		return false
+	}
+}
+
+// SubscribeFor decodes the JSON messages of the channel. The handler receives an error, with a zero value, when a message can't be decoded.
+func SubscribeFor[T any](ctx context.Context, client Subscriber, channel string, handler func(T, error), options ...SubscribeOption) {
+	subscription, unsubscribe := client.Subscribe(ctx, channel, options...)
 
+	consumeFor(ctx, subscription, unsubscribe, func(_ string, value T, err error) {
+		handler(value, err)
+	})
+}
+
+// PSubscribeFor decodes the JSON messages of the channels matching the pattern, the handler receiving the channel of each message.
+func PSubscribeFor[T any](ctx context.Context, client PSubscriber, pattern string, handler func(string, T, error), options ...SubscribeOption) {
+	subscription, unsubscribe := client.PSubscribe(ctx, pattern, options...)
+
+	consumeFor(ctx, subscription, unsubscribe, handler)
+}
+
+// SSubscribeFor decodes the JSON messages of the sharded channel.
+func SSubscribeFor[T any](ctx context.Context, client SSubscriber, channel string, handler func(T, error), options ...SubscribeOption) {
+	subscription, unsubscribe := client.SSubscribe(ctx, channel, options...)
+
+	consumeFor(ctx, subscription, unsubscribe, func(_ string, value T, err error) {
+		handler(value, err)
+	})
+}
+
+func consumeFor[T any](ctx context.Context, subscription <-chan *redis.Message, unsubscribe func(context.Context), handler func(string, T, error)) {
 	concurrent.ChanUntilDone(ctx, subscription, func(item *redis.Message) {
 		var instance T
-		handler(instance, json.Unmarshal([]byte(item.Payload), &instance))
+
+		if err := json.Unmarshal([]byte(item.Payload), &instance); err != nil {
+			var zero T
+			handler(item.Channel, zero, fmt.Errorf("unmarshal message of `%s`: %w", item.Channel, err))
+
+			return
+		}
+
+		handler(item.Channel, instance, nil)
 	}, func() {
 		unsubscribe(context.WithoutCancel(ctx))
 	})
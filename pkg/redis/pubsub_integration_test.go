@@ -0,0 +1,67 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPubSubIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	integration := test.NewRedisIntegration(t)
+	integration.Bootstrap(context.Background(), "redis_pubsub")
+
+	defer integration.Close(context.Background())
+	defer integration.Reset()
+
+	client := integration.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	patterns, unsubscribePattern := client.PSubscribe(ctx, "events:*")
+	defer unsubscribePattern(context.Background())
+
+	shards, unsubscribeShard := client.SSubscribe(ctx, "{events}:shard")
+	defer unsubscribeShard(context.Background())
+
+	assert.Eventually(t, func() bool {
+		return client.PublishJSON(ctx, "events:users", "created") == nil
+	}, time.Second, time.Millisecond*50)
+
+	message := receive(t, ctx, patterns)
+	assert.Equal(t, "events:users", message.Channel)
+	assert.Equal(t, "events:*", message.Pattern)
+
+	assert.Eventually(t, func() bool {
+		return client.SPublish(ctx, "{events}:shard", "paid") == nil
+	}, time.Second, time.Millisecond*50)
+
+	message = receive(t, ctx, shards)
+	assert.Equal(t, "paid", message.Payload)
+}
+
+func receive[T any](t *testing.T, ctx context.Context, messages <-chan T) T {
+	t.Helper()
+
+	select {
+	case message, ok := <-messages:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+
+		return message
+	case <-ctx.Done():
+		t.Fatal("no message received")
+	}
+
+	var zero T
+
+	return zero
+}
@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSubscriber struct {
+	messages     []*redis.Message
+	unsubscribed bool
+}
+
+func (fs *fakeSubscriber) channel() <-chan *redis.Message {
+	output := make(chan *redis.Message, len(fs.messages))
+
+	for _, message := range fs.messages {
+		output <- message
+	}
+
+	close(output)
+
+	return output
+}
+
+func (fs *fakeSubscriber) Subscribe(_ context.Context, _ string, _ ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return fs.channel(), func(context.Context) { fs.unsubscribed = true }
+}
+
+func (fs *fakeSubscriber) PSubscribe(_ context.Context, _ string, _ ...SubscribeOption) (<-chan *redis.Message, func(context.Context)) {
+	return fs.channel(), func(context.Context) { fs.unsubscribed = true }
+}
+
+func TestSubscribeFor(t *testing.T) {
+	t.Parallel()
+
+	subscriber := &fakeSubscriber{messages: []*redis.Message{
+		{Channel: "ids", Payload: "8000"},
+		{Channel: "ids", Payload: "invalid"},
+	}}
+
+	var values []int
+	var errs []error
+
+	SubscribeFor(context.Background(), subscriber, "ids", func(value int, err error) {
+		values = append(values, value)
+		errs = append(errs, err)
+	})
+
+	assert.Equal(t, []int{8000, 0}, values)
+	assert.NoError(t, errs[0])
+	assert.ErrorContains(t, errs[1], "unmarshal message of `ids`")
+	assert.True(t, subscriber.unsubscribed)
+}
+
+func TestPSubscribeFor(t *testing.T) {
+	t.Parallel()
+
+	subscriber := &fakeSubscriber{messages: []*redis.Message{
+		{Channel: "events:users", Pattern: "events:*", Payload: `"created"`},
+		{Channel: "events:orders", Pattern: "events:*", Payload: `"paid"`},
+	}}
+
+	got := map[string]string{}
+
+	PSubscribeFor(context.Background(), subscriber, "events:*", func(channel string, value string, err error) {
+		assert.NoError(t, err)
+		got[channel] = value
+	})
+
+	assert.Equal(t, map[string]string{"events:users": "created", "events:orders": "paid"}, got)
+}
+
+func TestOnReconnect(t *testing.T) {
+	t.Parallel()
+
+	var called bool
+
+	var config subscribeConfig
+	OnReconnect(func(context.Context) { called = true })(&config)
+
+	config.onReconnect(context.Background())
+	assert.True(t, called)
+
+	assert.True(t, isSubscription("ssubscribe"))
+	assert.False(t, isSubscription("unsubscribe"))
+}
+
+func TestListenUnsubscribeTwice(t *testing.T) {
+	t.Parallel()
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	pubsub := client.Subscribe(context.Background(), "ids")
+
+	_, unsubscribe := listen(context.Background(), pubsub, "ids", nil, pubsub.Unsubscribe)
+
+	assert.NotPanics(t, func() {
+		unsubscribe(context.Background())
+		unsubscribe(context.Background())
+	})
+}
@@ -31,13 +31,23 @@ type Service struct {
 }
 
 type Config struct {
-	Username    string
-	Password    string
-	Address     []string
-	Database    int
-	PoolSize    int
-	MinIdleConn int
-	MaxIdleTime time.Duration
+	Username      string
+	Password      string
+	MasterName    string
+	TLSCA         string
+	TLSCert       string
+	TLSKey        string
+	TLSServerName string
+	Address       []string
+	Database      int
+	PoolSize      int
+	MinIdleConn   int
+	MaxIdleTime   time.Duration
+	DialTimeout   time.Duration
+	ReadTimeout   time.Duration
+	WriteTimeout  time.Duration
+	TLS           bool
+	ReadOnly      bool
 }
 
 func Flags(fs *flag.FlagSet, prefix string, overrides ...flags.Override) *Config {
@@ -47,6 +57,19 @@ func Flags(fs *flag.FlagSet, prefix string, overrides ...flags.Override) *Config
 	flags.New("Username", "Redis Username, if any").Prefix(prefix).DocPrefix("redis").StringVar(fs, &config.Username, "", overrides)
 	flags.New("Password", "Redis Password, if any").Prefix(prefix).DocPrefix("redis").StringVar(fs, &config.Password, "", overrides)
 	flags.New("Database", "Redis Database").Prefix(prefix).DocPrefix("redis").IntVar(fs, &config.Database, 0, overrides)
+	flags.New("MasterName", "Redis Sentinel master name, Address being the sentinels").Prefix(prefix).DocPrefix("redis").StringVar(fs, &config.MasterName, "", overrides)
+	flags.New("PoolSize", "Redis Pool Size (default GOMAXPROCS*10)").Prefix(prefix).DocPrefix("redis").IntVar(fs, &config.PoolSize, 0, overrides)
+	flags.New("MinIdleConn", "Redis Minimum Idle Connections (default GOMAXPROCS)").Prefix(prefix).DocPrefix("redis").IntVar(fs, &config.MinIdleConn, 0, overrides)
+	flags.New("MaxIdleTime", "Redis Maximum Idle Time of a connection").Prefix(prefix).DocPrefix("redis").DurationVar(fs, &config.MaxIdleTime, time.Minute*30, overrides)
+	flags.New("DialTimeout", "Redis Dial Timeout").Prefix(prefix).DocPrefix("redis").DurationVar(fs, &config.DialTimeout, time.Second*5, overrides)
+	flags.New("ReadTimeout", "Redis Read Timeout").Prefix(prefix).DocPrefix("redis").DurationVar(fs, &config.ReadTimeout, time.Second*3, overrides)
+	flags.New("WriteTimeout", "Redis Write Timeout").Prefix(prefix).DocPrefix("redis").DurationVar(fs, &config.WriteTimeout, time.Second*3, overrides)
+	flags.New("ReadOnly", "Redis Cluster read-only commands on replicas").Prefix(prefix).DocPrefix("redis").BoolVar(fs, &config.ReadOnly, false, overrides)
+	flags.New("Tls", "Redis TLS, enabled with any other TLS flag").Prefix(prefix).DocPrefix("redis").BoolVar(fs, &config.TLS, false, overrides)
+	flags.New("TlsCa", "Redis TLS CA file, system pool if blank").Prefix(prefix).DocPrefix("redis").StringVar(fs, &config.TLSCA, "", overrides)
+	flags.New("TlsCert", "Redis TLS client certificate file").Prefix(prefix).DocPrefix("redis").StringVar(fs, &config.TLSCert, "", overrides)
+	flags.New("TlsKey", "Redis TLS client key file").Prefix(prefix).DocPrefix("redis").StringVar(fs, &config.TLSKey, "", overrides)
+	flags.New("TlsServerName", "Redis TLS server name, host of Address if blank").Prefix(prefix).DocPrefix("redis").StringVar(fs, &config.TLSServerName, "", overrides)
 
 	return &config
 }
@@ -66,13 +89,27 @@ func New(ctx context.Context, config *Config, meter metric.MeterProvider, tracer
 		config.PoolSize = gomaxprocs * 10
 	}
 
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return Noop{}, fmt.Errorf("tls: %w", err)
+	}
+
 	service := &Service{
-		isCluster: len(config.Address) > 1,
+		isCluster: len(config.MasterName) == 0 && len(config.Address) > 1,
 		client: redis.NewUniversalClient(&redis.UniversalOptions{
-			Addrs:    config.Address,
-			Username: config.Username,
-			Password: config.Password,
-			DB:       config.Database,
+			Addrs:           config.Address,
+			MasterName:      config.MasterName,
+			Username:        config.Username,
+			Password:        config.Password,
+			DB:              config.Database,
+			PoolSize:        config.PoolSize,
+			MinIdleConns:    config.MinIdleConn,
+			ConnMaxIdleTime: config.MaxIdleTime,
+			DialTimeout:     config.DialTimeout,
+			ReadTimeout:     config.ReadTimeout,
+			WriteTimeout:    config.WriteTimeout,
+			ReadOnly:        config.ReadOnly,
+			TLSConfig:       tlsConfig,
 		}),
 	}
 
@@ -234,6 +271,23 @@ func (s *Service) Expire(ctx context.Context, ttl time.Duration, keys ...string)
 	return s.execPipeline(ctx, pipeline)
 }
 
+// TTL returns the remaining time to live of the key, zero when it doesn't expire. The boolean is false when the key doesn't exist.
+func (s *Service) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ttl, err := s.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, false, fmt.Errorf("exec ttl: %w", err)
+	}
+
+	switch ttl {
+	case -2:
+		return 0, false, nil
+	case -1:
+		return 0, true, nil
+	default:
+		return ttl, true, nil
+	}
+}
+
 func (s *Service) Delete(ctx context.Context, keys ...string) (err error) {
 	if len(keys) == 0 {
 		return nil
@@ -308,7 +362,11 @@ func (s *Service) Scan(ctx context.Context, pattern string, output chan<- string
 		}
 
 		for _, key := range keys {
-			output <- key
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("exec scan: %w", ctx.Err())
+			case output <- key:
+			}
 		}
 
 		if cursor == 0 {
@@ -319,26 +377,14 @@ func (s *Service) Scan(ctx context.Context, pattern string, output chan<- string
 	return nil
 }
 
-func (s *Service) Exclusive(ctx context.Context, name string, timeout time.Duration, action func(context.Context) error) (bool, error) {
-	acquired, err := s.client.SetNX(ctx, name, "acquired", timeout).Result()
-	if err != nil {
-		return false, fmt.Errorf("exec setnx: %w", err)
-	}
-
-	if !acquired {
-		return false, nil
+// RunScript runs the Lua script, loading it only when it's not already cached by the server.
+func (s *Service) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...any) (any, error) {
+	output, err := script.Run(ctx, s.client, keys, args...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("exec script: %w", err)
 	}
 
-	actionCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	err = action(actionCtx)
-
-	if delErr := s.client.Del(ctx, name).Err(); delErr != nil {
-		err = errors.Join(err, delErr)
-	}
-
-	return true, err
+	return output, nil
 }
 
 func (s *Service) Pipeline() redis.Pipeliner {
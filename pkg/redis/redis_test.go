@@ -18,10 +18,36 @@ func TestFlags(t *testing.T) {
     	[redis] Redis Address host:port (blank to disable) ${SIMPLE_ADDRESS}, as a string slice, environment variable separated by "," (default [127.0.0.1:6379])
   -database int
     	[redis] Redis Database ${SIMPLE_DATABASE}
+  -dialTimeout duration
+    	[redis] Redis Dial Timeout ${SIMPLE_DIAL_TIMEOUT} (default 5s)
+  -masterName string
+    	[redis] Redis Sentinel master name, Address being the sentinels ${SIMPLE_MASTER_NAME}
+  -maxIdleTime duration
+    	[redis] Redis Maximum Idle Time of a connection ${SIMPLE_MAX_IDLE_TIME} (default 30m0s)
+  -minIdleConn int
+    	[redis] Redis Minimum Idle Connections (default GOMAXPROCS) ${SIMPLE_MIN_IDLE_CONN}
   -password string
     	[redis] Redis Password, if any ${SIMPLE_PASSWORD}
+  -poolSize int
+    	[redis] Redis Pool Size (default GOMAXPROCS*10) ${SIMPLE_POOL_SIZE}
+  -readOnly
+    	[redis] Redis Cluster read-only commands on replicas ${SIMPLE_READ_ONLY}
+  -readTimeout duration
+    	[redis] Redis Read Timeout ${SIMPLE_READ_TIMEOUT} (default 3s)
+  -tls
+    	[redis] Redis TLS, enabled with any other TLS flag ${SIMPLE_TLS}
+  -tlsCa string
+    	[redis] Redis TLS CA file, system pool if blank ${SIMPLE_TLS_CA}
+  -tlsCert string
+    	[redis] Redis TLS client certificate file ${SIMPLE_TLS_CERT}
+  -tlsKey string
+    	[redis] Redis TLS client key file ${SIMPLE_TLS_KEY}
+  -tlsServerName string
+    	[redis] Redis TLS server name, host of Address if blank ${SIMPLE_TLS_SERVER_NAME}
   -username string
     	[redis] Redis Username, if any ${SIMPLE_USERNAME}
+  -writeTimeout duration
+    	[redis] Redis Write Timeout ${SIMPLE_WRITE_TIMEOUT} (default 3s)
 `,
 		},
 	}
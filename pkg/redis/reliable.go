@@ -0,0 +1,321 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const reliableBlock = time.Second * 5
+
+var errNoDelivery = errors.New("no delivery")
+
+// retryPrefix marks a requeued message, followed by its retry count and its payload. A payload pushed as JSON never starts with a NUL byte.
+const retryPrefix = "\x00retry:"
+
+// nackScript removes the message from the processing list, then pushes it to the given list.
+var nackScript = redis.NewScript(`
+if redis.call("LREM", KEYS[1], -1, ARGV[1]) == 0 then
+  return 0
+end
+
+redis.call("LPUSH", KEYS[2], ARGV[2])
+
+return 1
+`)
+
+// recoverScript moves back the processing list of a consumer without heartbeat to the queue, oldest messages first. Each move counts as a retry.
+var recoverScript = redis.NewScript(`
+if ARGV[3] ~= "force" and redis.call("EXISTS", KEYS[4]) == 1 then
+  return 0
+end
+
+local maxRetry = tonumber(ARGV[1])
+local prefix = ARGV[4]
+local count = 0
+
+while true do
+  local element = redis.call("LPOP", KEYS[1])
+  if not element then
+    break
+  end
+
+  local payload = element
+  local retry = 0
+
+  if string.sub(element, 1, #prefix) == prefix then
+    local separator = string.find(element, ":", #prefix + 1, true)
+    if separator then
+      retry = tonumber(string.sub(element, #prefix + 1, separator - 1)) or 0
+      payload = string.sub(element, separator + 1)
+    end
+  end
+
+  retry = retry + 1
+
+  if maxRetry > 0 and retry > maxRetry then
+    redis.call("LPUSH", KEYS[3], payload)
+  else
+    redis.call("RPUSH", KEYS[2], prefix .. retry .. ":" .. payload)
+  end
+
+  count = count + 1
+end
+
+redis.call("SREM", KEYS[5], ARGV[2])
+
+return count
+`)
+
+// ReliableConfig configures PullReliable. Consumer has to be unique and stable across restarts of the same instance, e.g. the hostname.
+type ReliableConfig struct {
+	Consumer string
+	// VisibilityTimeout is the duration after which the messages of a consumer without heartbeat are delivered again. It has to be positive. The heartbeat is the one of the consumer, not of each message: a message whose handler is stuck in a running consumer is never delivered again.
+	VisibilityTimeout time.Duration
+	// MaxRetry is the number of deliveries after the first one before moving the message to the dead-letter list, zero retries indefinitely.
+	MaxRetry int
+}
+
+func (c ReliableConfig) validate() error {
+	if c.VisibilityTimeout <= 0 {
+		return fmt.Errorf("invalid visibility timeout `%s`", c.VisibilityTimeout)
+	}
+
+	return nil
+}
+
+// Delivery is a message pulled from a reliable queue. It has to be acknowledged with Ack, or released with Nack for a retry.
+type Delivery struct {
+	ack     func(context.Context) error
+	nack    func(context.Context) error
+	Payload string
+	Retry   int
+}
+
+func (d Delivery) Ack(ctx context.Context) error {
+	if d.ack == nil {
+		return errNoDelivery
+	}
+
+	return d.ack(ctx)
+}
+
+func (d Delivery) Nack(ctx context.Context) error {
+	if d.nack == nil {
+		return errNoDelivery
+	}
+
+	return d.nack(ctx)
+}
+
+type reliableKeys struct {
+	consumer   string
+	queue      string
+	processing string
+	heartbeat  string
+	consumers  string
+	dead       string
+}
+
+// newReliableKeys uses the queue key as hash tag, so every key lives on the same cluster slot.
+func newReliableKeys(key, consumer string) reliableKeys {
+	return reliableKeys{
+		consumer:   consumer,
+		queue:      key,
+		processing: fmt.Sprintf("{%s}:processing:%s", key, consumer),
+		heartbeat:  fmt.Sprintf("{%s}:heartbeat:%s", key, consumer),
+		consumers:  fmt.Sprintf("{%s}:consumers", key),
+		dead:       DeadLetterKey(key),
+	}
+}
+
+// parseElement extracts the payload and the retry count of a message of the queue.
+func parseElement(element string) (string, int) {
+	content, ok := strings.CutPrefix(element, retryPrefix)
+	if !ok {
+		return element, 0
+	}
+
+	rawRetry, payload, ok := strings.Cut(content, ":")
+	if !ok {
+		return element, 0
+	}
+
+	retry, err := strconv.Atoi(rawRetry)
+	if err != nil {
+		return element, 0
+	}
+
+	return payload, retry
+}
+
+// retryElement is the message requeued for its given retry, the count being carried by the message so identical payloads are counted apart.
+func retryElement(payload string, retry int) string {
+	return retryPrefix + strconv.Itoa(retry) + ":" + payload
+}
+
+// DeadLetterKey is the list of the messages of the queue that exceeded their max retry.
+func DeadLetterKey(key string) string {
+	return fmt.Sprintf("{%s}:dead", key)
+}
+
+// PullReliable moves each message of the queue filled by Push into a processing list of the consumer until it's acknowledged, so it's not lost if the consumer dies. It runs until the context is done.
+func (s *Service) PullReliable(ctx context.Context, key string, config ReliableConfig, handler func(context.Context, Delivery, error)) {
+	if err := config.validate(); err != nil {
+		handler(ctx, Delivery{}, err)
+
+		return
+	}
+
+	keys := newReliableKeys(key, config.Consumer)
+
+	// Messages of a previous run of the same consumer are delivered again.
+	if err := s.recoverProcessing(ctx, keys, config.MaxRetry, true); err != nil {
+		handler(ctx, Delivery{}, fmt.Errorf("recover: %w", err))
+	}
+
+	if err := s.heartbeat(ctx, keys, config.VisibilityTimeout); err != nil {
+		handler(ctx, Delivery{}, fmt.Errorf("heartbeat: %w", err))
+	}
+
+	go s.maintain(ctx, keys, config)
+
+	var wait backoff
+
+	for {
+		element, err := s.client.BLMove(ctx, keys.queue, keys.processing, "RIGHT", "LEFT", reliableBlock).Result()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			handler(ctx, Delivery{}, fmt.Errorf("blmove: %w", err))
+
+			if !wait.sleep(ctx) {
+				return
+			}
+
+			continue
+		}
+
+		wait.reset()
+
+		handler(ctx, s.delivery(keys, config.MaxRetry, element), nil)
+	}
+}
+
+func (s *Service) delivery(keys reliableKeys, maxRetry int, element string) Delivery {
+	payload, retry := parseElement(element)
+
+	return Delivery{
+		Payload: payload,
+		Retry:   retry,
+		ack: func(ctx context.Context) error {
+			if err := s.client.LRem(ctx, keys.processing, -1, element).Err(); err != nil {
+				return fmt.Errorf("lrem: %w", err)
+			}
+
+			return nil
+		},
+		nack: func(ctx context.Context) error {
+			destination, requeued := keys.queue, retryElement(payload, retry+1)
+			if maxRetry > 0 && retry+1 > maxRetry {
+				destination, requeued = keys.dead, payload
+			}
+
+			if err := nackScript.Run(ctx, s.client, []string{keys.processing, destination}, element, requeued).Err(); err != nil {
+				return fmt.Errorf("exec nack script: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// maintain refreshes the heartbeat of the consumer and recovers the messages of dead consumers.
+func (s *Service) maintain(ctx context.Context, keys reliableKeys, config ReliableConfig) {
+	ticker := time.NewTicker(max(config.VisibilityTimeout/3, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.heartbeat(ctx, keys, config.VisibilityTimeout); err != nil {
+			slog.LogAttrs(ctx, slog.LevelError, "reliable queue heartbeat", slog.String("key", keys.queue), slog.Any("error", err))
+		}
+
+		consumers, err := s.client.SMembers(ctx, keys.consumers).Result()
+		if err != nil {
+			slog.LogAttrs(ctx, slog.LevelError, "list reliable queue consumers", slog.String("key", keys.queue), slog.Any("error", err))
+
+			continue
+		}
+
+		for _, consumer := range consumers {
+			if consumer == config.Consumer {
+				continue
+			}
+
+			if err := s.recoverProcessing(ctx, newReliableKeys(keys.queue, consumer), config.MaxRetry, false); err != nil {
+				slog.LogAttrs(ctx, slog.LevelError, "recover reliable queue consumer", slog.String("key", keys.queue), slog.String("consumer", consumer), slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (s *Service) heartbeat(ctx context.Context, keys reliableKeys, visibilityTimeout time.Duration) error {
+	pipeline := s.client.Pipeline()
+
+	pipeline.SAdd(ctx, keys.consumers, keys.consumer)
+	pipeline.Set(ctx, keys.heartbeat, time.Now().Unix(), visibilityTimeout)
+
+	return s.execPipeline(ctx, pipeline)
+}
+
+// recoverProcessing moves back the processing list of the consumer to the queue, when it has no heartbeat or when forced.
+func (s *Service) recoverProcessing(ctx context.Context, keys reliableKeys, maxRetry int, force bool) error {
+	var mode string
+	if force {
+		mode = "force"
+	}
+
+	count, err := recoverScript.Run(ctx, s.client, []string{keys.processing, keys.queue, keys.dead, keys.heartbeat, keys.consumers}, maxRetry, keys.consumer, mode, retryPrefix).Int()
+	if err != nil {
+		return fmt.Errorf("exec recover script: %w", err)
+	}
+
+	if count > 0 {
+		slog.LogAttrs(ctx, slog.LevelWarn, "recovered reliable queue messages", slog.String("key", keys.queue), slog.String("consumer", keys.consumer), slog.Int("count", count))
+	}
+
+	return nil
+}
+
+func PullReliableFor[T any](ctx context.Context, client Client, key string, config ReliableConfig, handler func(context.Context, Delivery, T, error)) {
+	client.PullReliable(ctx, key, config, func(ctx context.Context, delivery Delivery, err error) {
+		var instance T
+
+		if err != nil {
+			handler(ctx, delivery, instance, err)
+		} else if unmarshalErr := json.Unmarshal([]byte(delivery.Payload), &instance); unmarshalErr != nil {
+			handler(ctx, delivery, instance, fmt.Errorf("unmarshal: %w", unmarshalErr))
+		} else {
+			handler(ctx, delivery, instance, nil)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+	"github.com/ViBiOh/httputils/v4/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullReliableIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	integration := test.NewRedisIntegration(t)
+	integration.Bootstrap(context.Background(), "redis_reliable")
+
+	defer integration.Close(context.Background())
+	defer integration.Reset()
+
+	client := integration.Client()
+
+	assert.NoError(t, client.Push(context.Background(), "tasks", 1))
+	assert.NoError(t, client.Push(context.Background(), "tasks", 2))
+	assert.NoError(t, client.Push(context.Background(), "tasks", 2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	var acked []int
+	var retries []int
+
+	redis.PullReliableFor(ctx, client, "tasks", redis.ReliableConfig{Consumer: "test", VisibilityTimeout: time.Second, MaxRetry: 1}, func(ctx context.Context, delivery redis.Delivery, value int, err error) {
+		assert.NoError(t, err)
+
+		if value == 1 {
+			acked = append(acked, value)
+			assert.NoError(t, delivery.Ack(ctx))
+
+			return
+		}
+
+		retries = append(retries, delivery.Retry)
+		assert.NoError(t, delivery.Nack(ctx))
+
+		if len(retries) == 4 {
+			cancel()
+		}
+	})
+
+	assert.Equal(t, []int{1}, acked)
+	assert.ElementsMatch(t, []int{0, 0, 1, 1}, retries)
+
+	pipeline := client.Pipeline()
+	deadLetters := pipeline.LRange(context.Background(), redis.DeadLetterKey("tasks"), 0, -1)
+
+	_, err := pipeline.Exec(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "2"}, deadLetters.Val())
+}
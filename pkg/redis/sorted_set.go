@@ -0,0 +1,250 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SortedSetter is the subset of Client used for manipulating a sorted set.
+type SortedSetter interface {
+	ZAdd(ctx context.Context, key string, members ...redis.Z) error
+	ZIncrBy(ctx context.Context, key, member string, incr float64) (float64, error)
+	ZRange(ctx context.Context, key string, start, stop int64, reverse bool) ([]redis.Z, error)
+	ZRangeByScore(ctx context.Context, key string, minScore, maxScore float64, offset, count int64) ([]redis.Z, error)
+	ZRank(ctx context.Context, key, member string, reverse bool) (int64, bool, error)
+	ZRem(ctx context.Context, key string, members ...string) error
+}
+
+func (s *Service) ZAdd(ctx context.Context, key string, members ...redis.Z) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	if err := s.client.ZAdd(ctx, key, members...).Err(); err != nil {
+		return fmt.Errorf("zadd: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) ZIncrBy(ctx context.Context, key, member string, incr float64) (float64, error) {
+	score, err := s.client.ZIncrBy(ctx, key, incr, member).Result()
+	if err != nil {
+		return 0, fmt.Errorf("zincrby: %w", err)
+	}
+
+	return score, nil
+}
+
+// ZRange returns the members between the start and stop ranks included, by descending score if reverse.
+func (s *Service) ZRange(ctx context.Context, key string, start, stop int64, reverse bool) ([]redis.Z, error) {
+	members, err := s.client.ZRangeArgsWithScores(ctx, redis.ZRangeArgs{
+		Key:   key,
+		Start: start,
+		Stop:  stop,
+		Rev:   reverse,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("zrange: %w", err)
+	}
+
+	return members, nil
+}
+
+// ZRangeByScore returns the members with a score between minScore and maxScore included, by ascending score. A zero count returns all of them.
+func (s *Service) ZRangeByScore(ctx context.Context, key string, minScore, maxScore float64, offset, count int64) ([]redis.Z, error) {
+	args := redis.ZRangeBy{
+		Min:    scoreBound(minScore),
+		Max:    scoreBound(maxScore),
+		Offset: offset,
+		Count:  count,
+	}
+
+	if count == 0 {
+		args.Count = -1
+	}
+
+	members, err := s.client.ZRangeByScoreWithScores(ctx, key, &args).Result()
+	if err != nil {
+		return nil, fmt.Errorf("zrangebyscore: %w", err)
+	}
+
+	return members, nil
+}
+
+// ZRank returns the rank of the member, by descending score if reverse, and false if it's absent.
+func (s *Service) ZRank(ctx context.Context, key, member string, reverse bool) (int64, bool, error) {
+	command := s.client.ZRank
+	if reverse {
+		command = s.client.ZRevRank
+	}
+
+	rank, err := command(ctx, key, member).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("zrank: %w", err)
+	}
+
+	return rank, true, nil
+}
+
+func (s *Service) ZRem(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	values := make([]any, len(members))
+	for index, member := range members {
+		values[index] = member
+	}
+
+	if err := s.client.ZRem(ctx, key, values...).Err(); err != nil {
+		return fmt.Errorf("zrem: %w", err)
+	}
+
+	return nil
+}
+
+func scoreBound(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(score, 'f', -1, 64)
+	}
+}
+
+// Scored is a member of a sorted set with its score.
+type Scored[T any] struct {
+	Value T
+	Score float64
+}
+
+// SortedSetFor manipulates a sorted set of JSON members. The JSON encoding identifies the member, so it has to be stable for a given value.
+type SortedSetFor[T any] struct {
+	client SortedSetter
+	key    string
+}
+
+func NewSortedSetFor[T any](client SortedSetter, key string) SortedSetFor[T] {
+	return SortedSetFor[T]{
+		client: client,
+		key:    key,
+	}
+}
+
+// Add adds the members or updates their score.
+func (s SortedSetFor[T]) Add(ctx context.Context, members ...Scored[T]) error {
+	values := make([]redis.Z, len(members))
+
+	for index, member := range members {
+		payload, err := json.Marshal(member.Value)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+
+		values[index] = redis.Z{Score: member.Score, Member: string(payload)}
+	}
+
+	return s.client.ZAdd(ctx, s.key, values...)
+}
+
+// Incr increments the score of the member by incr, adding it if absent, and returns its new score.
+func (s SortedSetFor[T]) Incr(ctx context.Context, value T, incr float64) (float64, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return 0, fmt.Errorf("marshal: %w", err)
+	}
+
+	return s.client.ZIncrBy(ctx, s.key, string(payload), incr)
+}
+
+// RangeByScore returns the members with a score between minScore and maxScore included, by ascending score. Use math.Inf for unbounded limits, and a zero count for all the members.
+func (s SortedSetFor[T]) RangeByScore(ctx context.Context, minScore, maxScore float64, offset, count int64) ([]Scored[T], error) {
+	members, err := s.client.ZRangeByScore(ctx, s.key, minScore, maxScore, offset, count)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeScored[T](members)
+}
+
+// Rank returns the rank of the member, zero being the lowest score or the highest if reverse, and false if it's absent.
+func (s SortedSetFor[T]) Rank(ctx context.Context, value T, reverse bool) (int64, bool, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("marshal: %w", err)
+	}
+
+	return s.client.ZRank(ctx, s.key, string(payload), reverse)
+}
+
+// Page returns count members from the cursor, by descending score if reverse, and the cursor of the next page. Like SCAN, iteration starts with a zero cursor and is complete when the returned cursor is zero.
+func (s SortedSetFor[T]) Page(ctx context.Context, cursor uint64, count int64, reverse bool) ([]Scored[T], uint64, error) {
+	if count <= 0 {
+		return nil, 0, fmt.Errorf("invalid count `%d`", count)
+	}
+
+	start := int64(cursor)
+
+	members, err := s.client.ZRange(ctx, s.key, start, start+count-1, reverse)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	output, err := decodeScored[T](members)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if int64(len(members)) < count {
+		return output, 0, nil
+	}
+
+	return output, cursor + uint64(count), nil
+}
+
+func (s SortedSetFor[T]) Remove(ctx context.Context, values ...T) error {
+	members := make([]string, len(values))
+
+	for index, value := range values {
+		payload, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+
+		members[index] = string(payload)
+	}
+
+	return s.client.ZRem(ctx, s.key, members...)
+}
+
+func decodeScored[T any](members []redis.Z) ([]Scored[T], error) {
+	output := make([]Scored[T], len(members))
+
+	for index, member := range members {
+		content, ok := member.Member.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected member type `%T`", member.Member)
+		}
+
+		if err := json.Unmarshal([]byte(content), &output[index].Value); err != nil {
+			return nil, fmt.Errorf("unmarshal: %w", err)
+		}
+
+		output[index].Score = member.Score
+	}
+
+	return output, nil
+}
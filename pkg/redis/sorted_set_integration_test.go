@@ -0,0 +1,75 @@
+package redis_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+	"github.com/ViBiOh/httputils/v4/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedSetIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	integration := test.NewRedisIntegration(t)
+	integration.Bootstrap(context.Background(), "redis_sorted_set")
+
+	defer integration.Close(context.Background())
+	defer integration.Reset()
+
+	ctx := context.Background()
+	leaderboard := redis.NewSortedSetFor[string](integration.Client(), "leaderboard")
+
+	assert.NoError(t, leaderboard.Add(ctx,
+		redis.Scored[string]{Value: "alice", Score: 10},
+		redis.Scored[string]{Value: "bob", Score: 20},
+		redis.Scored[string]{Value: "carol", Score: 30},
+	))
+
+	score, err := leaderboard.Incr(ctx, "alice", 15)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(25), score)
+
+	rank, ok, err := leaderboard.Rank(ctx, "alice", true)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), rank)
+
+	_, ok, err = leaderboard.Rank(ctx, "dave", true)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	members, err := leaderboard.RangeByScore(ctx, 20, math.Inf(1), 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []redis.Scored[string]{{Value: "bob", Score: 20}, {Value: "alice", Score: 25}, {Value: "carol", Score: 30}}, members)
+
+	page, cursor, err := leaderboard.Page(ctx, 0, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []redis.Scored[string]{{Value: "carol", Score: 30}, {Value: "alice", Score: 25}}, page)
+
+	page, cursor, err = leaderboard.Page(ctx, cursor, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []redis.Scored[string]{{Value: "bob", Score: 20}}, page)
+	assert.Equal(t, uint64(0), cursor)
+
+	assert.NoError(t, leaderboard.Remove(ctx, "bob"))
+
+	members, err = leaderboard.RangeByScore(ctx, math.Inf(-1), math.Inf(1), 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	counters := redis.NewHashFor[int64](integration.Client(), "counters")
+
+	value, err := counters.Incr(ctx, "views", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), value)
+
+	got, ok, err := counters.Get(ctx, "views")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), got)
+}
@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSortedSet holds the members by ascending score.
+type fakeSortedSet struct {
+	members []redis.Z
+}
+
+func (fs *fakeSortedSet) ZAdd(_ context.Context, _ string, members ...redis.Z) error {
+	fs.members = append(fs.members, members...)
+
+	return nil
+}
+
+func (fs *fakeSortedSet) ZIncrBy(_ context.Context, _, _ string, incr float64) (float64, error) {
+	return incr, nil
+}
+
+func (fs *fakeSortedSet) ZRange(_ context.Context, _ string, start, stop int64, _ bool) ([]redis.Z, error) {
+	length := int64(len(fs.members))
+	if start >= length {
+		return nil, nil
+	}
+
+	return fs.members[start:min(stop+1, length)], nil
+}
+
+func (fs *fakeSortedSet) ZRangeByScore(_ context.Context, _ string, minScore, maxScore float64, _, _ int64) ([]redis.Z, error) {
+	var output []redis.Z
+
+	for _, member := range fs.members {
+		if member.Score >= minScore && member.Score <= maxScore {
+			output = append(output, member)
+		}
+	}
+
+	return output, nil
+}
+
+func (fs *fakeSortedSet) ZRank(_ context.Context, _, member string, _ bool) (int64, bool, error) {
+	for index, item := range fs.members {
+		if item.Member == member {
+			return int64(index), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func (fs *fakeSortedSet) ZRem(_ context.Context, _ string, _ ...string) error {
+	return nil
+}
+
+func TestSortedSetFor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	set := &fakeSortedSet{}
+	leaderboard := NewSortedSetFor[profile](set, "leaderboard")
+
+	assert.NoError(t, leaderboard.Add(ctx,
+		Scored[profile]{Value: profile{Name: "Alice"}, Score: 1},
+		Scored[profile]{Value: profile{Name: "Bob"}, Score: 2},
+		Scored[profile]{Value: profile{Name: "Carol"}, Score: 3},
+	))
+	assert.Equal(t, `{"name":"Alice","age":0}`, set.members[0].Member)
+
+	got, err := leaderboard.RangeByScore(ctx, 2, math.Inf(1), 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []Scored[profile]{{Value: profile{Name: "Bob"}, Score: 2}, {Value: profile{Name: "Carol"}, Score: 3}}, got)
+
+	rank, ok, err := leaderboard.Rank(ctx, profile{Name: "Bob"}, false)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), rank)
+
+	page, cursor, err := leaderboard.Page(ctx, 0, 2, false)
+	assert.NoError(t, err)
+	assert.Len(t, page, 2)
+	assert.Equal(t, uint64(2), cursor)
+
+	page, cursor, err = leaderboard.Page(ctx, cursor, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []Scored[profile]{{Value: profile{Name: "Carol"}, Score: 3}}, page)
+	assert.Equal(t, uint64(0), cursor)
+
+	_, _, err = leaderboard.Page(ctx, 0, 0, false)
+	assert.ErrorContains(t, err, "invalid count")
+
+	set.members = append(set.members, redis.Z{Member: "{"})
+
+	_, err = leaderboard.RangeByScore(ctx, math.Inf(-1), math.Inf(1), 0, 0)
+	assert.ErrorContains(t, err, "unmarshal")
+}
+
+func TestScoreBound(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "-inf", scoreBound(math.Inf(-1)))
+	assert.Equal(t, "+inf", scoreBound(math.Inf(1)))
+	assert.Equal(t, "1.5", scoreBound(1.5))
+}
@@ -0,0 +1,212 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	streamPayloadField = "payload"
+	streamReadCount    = 10
+	streamBlock        = time.Second * 5
+)
+
+// Streamer is the subset of Client used for consuming a stream with a consumer group.
+type Streamer interface {
+	XGroup(ctx context.Context, stream, group string) error
+	XReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]redis.XMessage, error)
+	XAutoClaim(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]redis.XMessage, error)
+	XAck(ctx context.Context, stream, group string, ids ...string) error
+}
+
+// XAdd appends the JSON of the value to the stream. A positive maxLen trims the stream to approximately this length.
+func (s *Service) XAdd(ctx context.Context, stream string, maxLen int64, value any) (string, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	args := redis.XAddArgs{
+		Stream: stream,
+		Values: []any{streamPayloadField, payload},
+	}
+
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+
+	id, err := s.client.XAdd(ctx, &args).Result()
+	if err != nil {
+		return "", fmt.Errorf("xadd: %w", err)
+	}
+
+	return id, nil
+}
+
+// XGroup creates the consumer group and the stream if needed, the group reads only new messages. It's a no-op when the group already exists.
+func (s *Service) XGroup(ctx context.Context, stream, group string) error {
+	if err := s.client.XGroupCreateMkStream(ctx, stream, group, "$").Err(); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("xgroup create: %w", err)
+	}
+
+	return nil
+}
+
+// XReadGroup reads new messages for the consumer, waiting at most block for one. It returns no message on timeout.
+func (s *Service) XReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("xreadgroup: %w", err)
+	}
+
+	var output []redis.XMessage
+
+	for _, item := range streams {
+		output = append(output, item.Messages...)
+	}
+
+	return output, nil
+}
+
+// XAutoClaim transfers to the consumer the pending messages not acknowledged after minIdle, e.g. read by a dead consumer. It claims at most count messages, all of them when zero.
+func (s *Service) XAutoClaim(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]redis.XMessage, error) {
+	var output []redis.XMessage
+
+	start := "0-0"
+
+	for {
+		var remaining int64
+		if count > 0 {
+			remaining = count - int64(len(output))
+		}
+
+		messages, next, err := s.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: consumer,
+			MinIdle:  minIdle,
+			Start:    start,
+			Count:    remaining,
+		}).Result()
+		if err != nil {
+			return output, fmt.Errorf("xautoclaim: %w", err)
+		}
+
+		output = append(output, messages...)
+
+		if next == "0-0" || len(next) == 0 || (count > 0 && int64(len(output)) >= count) {
+			return output, nil
+		}
+
+		start = next
+	}
+}
+
+func (s *Service) XAck(ctx context.Context, stream, group string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := s.client.XAck(ctx, stream, group, ids...).Err(); err != nil {
+		return fmt.Errorf("xack: %w", err)
+	}
+
+	return nil
+}
+
+// StreamMessage is a message read from a stream, it has to be acknowledged once handled or it's delivered again after the claim idle duration.
+type StreamMessage[T any] struct {
+	client Streamer
+	stream string
+	group  string
+	ID     string
+	Value  T
+}
+
+func (sm StreamMessage[T]) Ack(ctx context.Context) error {
+	return sm.client.XAck(ctx, sm.stream, sm.group, sm.ID)
+}
+
+// StreamFor consumes the stream in the consumer group until the context is done, decoding the JSON payload of each message. Messages pending for more than claimIdle, e.g. because their consumer died, are reclaimed by this consumer. A zero claimIdle disables the reclaim.
+func StreamFor[T any](ctx context.Context, client Streamer, stream, group, consumer string, claimIdle time.Duration, handler func(context.Context, StreamMessage[T], error)) error {
+	if err := client.XGroup(ctx, stream, group); err != nil {
+		return fmt.Errorf("create group: %w", err)
+	}
+
+	handle := func(messages []redis.XMessage) {
+		for _, message := range messages {
+			item := StreamMessage[T]{client: client, stream: stream, group: group, ID: message.ID}
+			handler(ctx, item, decodeStreamMessage(message, &item.Value))
+		}
+	}
+
+	block := streamBlock
+	if claimIdle > 0 {
+		block = min(block, claimIdle)
+	}
+
+	var lastClaim time.Time
+
+	for {
+		if claimIdle > 0 && time.Since(lastClaim) > claimIdle {
+			lastClaim = time.Now()
+
+			claimed, err := client.XAutoClaim(ctx, stream, group, consumer, claimIdle, streamReadCount)
+			if err != nil && ctx.Err() == nil {
+				handler(ctx, StreamMessage[T]{}, err)
+			}
+
+			handle(claimed)
+		}
+
+		messages, err := client.XReadGroup(ctx, stream, group, consumer, streamReadCount, block)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			handler(ctx, StreamMessage[T]{}, err)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+
+			continue
+		}
+
+		handle(messages)
+	}
+}
+
+func decodeStreamMessage(message redis.XMessage, output any) error {
+	payload, ok := message.Values[streamPayloadField].(string)
+	if !ok {
+		return fmt.Errorf("no payload in message `%s`", message.ID)
+	}
+
+	if err := json.Unmarshal([]byte(payload), output); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+	"github.com/ViBiOh/httputils/v4/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	integration := test.NewRedisIntegration(t)
+	integration.Bootstrap(context.Background(), "redis_stream")
+
+	defer integration.Close(context.Background())
+	defer integration.Reset()
+
+	client := integration.Client()
+	ctx := context.Background()
+
+	assert.NoError(t, client.XGroup(ctx, "events", "workers"))
+	assert.NoError(t, client.XGroup(ctx, "events", "workers"))
+
+	for id := range 3 {
+		_, err := client.XAdd(ctx, "events", 100, map[string]int{"id": id})
+		assert.NoError(t, err)
+	}
+
+	messages, err := client.XReadGroup(ctx, "events", "workers", "dead", 10, time.Millisecond*100)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 3)
+
+	assert.NoError(t, client.XAck(ctx, "events", "workers", messages[0].ID))
+
+	time.Sleep(time.Millisecond * 50)
+
+	claimed, err := client.XAutoClaim(ctx, "events", "workers", "alive", time.Millisecond*10, 1)
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+
+	claimed, err = client.XAutoClaim(ctx, "events", "workers", "alive", time.Millisecond*10, 10)
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+
+	_, err = client.XAdd(ctx, "events", 100, map[string]int{"id": 8000})
+	assert.NoError(t, err)
+
+	streamCtx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	var got int
+
+	assert.NoError(t, redis.StreamFor(streamCtx, client, "events", "workers", "alive", 0, func(ctx context.Context, message redis.StreamMessage[map[string]int], err error) {
+		assert.NoError(t, err)
+		assert.NoError(t, message.Ack(ctx))
+
+		got = message.Value["id"]
+		cancel()
+	}))
+
+	assert.Equal(t, 8000, got)
+}
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStreamer struct {
+	claimed  []redis.XMessage
+	messages []redis.XMessage
+	acked    []string
+	mutex    sync.Mutex
+}
+
+func (fs *fakeStreamer) XGroup(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (fs *fakeStreamer) XReadGroup(ctx context.Context, _, _, _ string, _ int64, _ time.Duration) ([]redis.XMessage, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if len(fs.messages) == 0 {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	output := fs.messages
+	fs.messages = nil
+
+	return output, nil
+}
+
+func (fs *fakeStreamer) XAutoClaim(_ context.Context, _, _, _ string, _ time.Duration, _ int64) ([]redis.XMessage, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	output := fs.claimed
+	fs.claimed = nil
+
+	return output, nil
+}
+
+func (fs *fakeStreamer) XAck(_ context.Context, _, _ string, ids ...string) error {
+	fs.acked = append(fs.acked, ids...)
+
+	return nil
+}
+
+func TestStreamFor(t *testing.T) {
+	t.Parallel()
+
+	streamer := &fakeStreamer{
+		claimed: []redis.XMessage{
+			{ID: "1-0", Values: map[string]any{streamPayloadField: `{"id":1}`}},
+		},
+		messages: []redis.XMessage{
+			{ID: "2-0", Values: map[string]any{streamPayloadField: `{"id":2}`}},
+			{ID: "3-0", Values: map[string]any{streamPayloadField: `invalid`}},
+			{ID: "4-0", Values: map[string]any{}},
+		},
+	}
+
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ids []int
+	var errs []error
+
+	err := StreamFor(ctx, streamer, "stream", "group", "consumer", time.Minute, func(ctx context.Context, message StreamMessage[item], err error) {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			ids = append(ids, message.Value.ID)
+			assert.NoError(t, message.Ack(ctx))
+		}
+
+		if message.ID == "4-0" {
+			cancel()
+		}
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.Equal(t, []string{"1-0", "2-0"}, streamer.acked)
+	assert.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[0], "unmarshal")
+	assert.ErrorContains(t, errs[1], "no payload")
+}
@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var deleteTagScript = redis.NewScript(`
+local members = redis.call("ZRANGE", KEYS[1], 0, -1)
+
+for i = 1, #members, 1000 do
+  redis.call("DEL", unpack(members, i, math.min(i + 999, #members)))
+end
+
+redis.call("DEL", KEYS[1])
+
+return #members
+`)
+
+// expireTagScript makes the set expire with its longest-lived member, never when one of them doesn't expire.
+var expireTagScript = redis.NewScript(`
+local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+if #last == 0 then
+  return 0
+end
+
+if last[2] == "inf" then
+  redis.call("PERSIST", KEYS[1])
+else
+  redis.call("PEXPIREAT", KEYS[1], last[2])
+end
+
+return 1
+`)
+
+// tagExpiration is the score of a tag member, the expiration of its key in unix milliseconds, or +inf if it doesn't expire.
+func tagExpiration(now time.Time, ttl time.Duration) float64 {
+	if ttl == 0 {
+		return math.Inf(1)
+	}
+
+	return float64(now.Add(ttl).UnixMilli())
+}
+
+// Tag adds the keys to the sorted set of each tag, scored by their expiration after the given ttl, zero for no expiration: expired members are pruned on each call. The expiration of a member is only extended, and a set expires with its longest-lived member, so it outlives its members when tagging with different ttl.
+func (s *Service) Tag(ctx context.Context, tagged map[string][]string, ttl time.Duration) error {
+	if len(tagged) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	score := tagExpiration(now, ttl)
+	expired := "(" + strconv.FormatInt(now.UnixMilli(), 10)
+
+	pipeline := s.client.Pipeline()
+
+	for tag, keys := range tagged {
+		members := make([]redis.Z, len(keys))
+		for index, key := range keys {
+			members[index] = redis.Z{Score: score, Member: key}
+		}
+
+		pipeline.ZAddArgs(ctx, tag, redis.ZAddArgs{GT: true, Members: members})
+		pipeline.ZRemRangeByScore(ctx, tag, "-inf", expired)
+
+		expireTagScript.Eval(ctx, pipeline, []string{tag})
+	}
+
+	return s.execPipeline(ctx, pipeline)
+}
+
+// DeleteTag deletes every key of the tag's set and the set itself. It's atomic, except on cluster where keys can live on different nodes.
+func (s *Service) DeleteTag(ctx context.Context, tag string) error {
+	if !s.isCluster {
+		if err := deleteTagScript.Run(ctx, s.client, []string{tag}).Err(); err != nil {
+			return fmt.Errorf("exec delete tag script: %w", err)
+		}
+
+		return nil
+	}
+
+	keys, err := s.client.ZRange(ctx, tag, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("exec zrange: %w", err)
+	}
+
+	return s.Delete(ctx, append(keys, tag)...)
+}
@@ -3,12 +3,39 @@ package redis
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"strings"
 	"time"
 )
 
+const (
+	minBackoff = time.Millisecond * 100
+	maxBackoff = time.Second * 30
+)
+
+// backoff doubles the wait between consecutive errors, up to maxBackoff.
+type backoff struct {
+	delay time.Duration
+}
+
+// sleep waits for the next delay, it returns false if the context is done meanwhile.
+func (b *backoff) sleep(ctx context.Context) bool {
+	b.delay = min(max(b.delay*2, minBackoff), maxBackoff)
+
+	timer := time.NewTimer(b.delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (b *backoff) reset() {
+	b.delay = 0
+}
+
 func (s *Service) Push(ctx context.Context, key string, value any) error {
 	if content, err := json.Marshal(value); err != nil {
 		return fmt.Errorf("marshal: %w", err)
@@ -20,22 +47,26 @@ func (s *Service) Push(ctx context.Context, key string, value any) error {
 }
 
 func (s *Service) Pull(ctx context.Context, key string, handler func(string, error)) {
+	var wait backoff
+
 	for {
 		content, err := s.client.BRPop(ctx, 0, key).Result()
 		if err != nil {
-			if errors.Is(err, context.Canceled) {
+			if ctx.Err() != nil {
 				return
 			}
 
-			if strings.HasSuffix(err.Error(), "connect: connection refused") {
-				time.Sleep(time.Minute)
-			} else {
-				handler("", err)
+			handler("", err)
+
+			if !wait.sleep(ctx) {
+				return
 			}
 
 			continue
 		}
 
+		wait.reset()
+
 		if len(content) == 2 {
 			handler(content[1], nil)
 		}
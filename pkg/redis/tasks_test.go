@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	var instance backoff
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.False(t, instance.sleep(ctx))
+	assert.Equal(t, minBackoff, instance.delay)
+
+	assert.False(t, instance.sleep(ctx))
+	assert.Equal(t, minBackoff*2, instance.delay)
+
+	instance.delay = time.Hour
+	assert.False(t, instance.sleep(ctx))
+	assert.Equal(t, maxBackoff, instance.delay)
+
+	instance.reset()
+	assert.Equal(t, time.Duration(0), instance.delay)
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// newTLSConfig returns nil when TLS is disabled. The server name defaults to the host of the address, set by the client.
+func newTLSConfig(config *Config) (*tls.Config, error) {
+	if !config.TLS && len(config.TLSCA) == 0 && len(config.TLSCert) == 0 && len(config.TLSKey) == 0 && len(config.TLSServerName) == 0 {
+		return nil, nil
+	}
+
+	output := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: config.TLSServerName,
+	}
+
+	if len(config.TLSCA) != 0 {
+		content, err := os.ReadFile(config.TLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("read ca: %w", err)
+		}
+
+		output.RootCAs = x509.NewCertPool()
+		if !output.RootCAs.AppendCertsFromPEM(content) {
+			return nil, errors.New("no certificate in ca")
+		}
+	}
+
+	if len(config.TLSCert) != 0 || len(config.TLSKey) != 0 {
+		certificate, err := tls.LoadX509KeyPair(config.TLSCert, config.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+
+		output.Certificates = []tls.Certificate{certificate}
+	}
+
+	return output, nil
+}
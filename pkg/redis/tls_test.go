@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	invalidCA := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(invalidCA, []byte("not a certificate"), 0o600))
+
+	cases := map[string]struct {
+		config     Config
+		wantNil    bool
+		wantServer string
+		wantErr    string
+	}{
+		"disabled": {
+			wantNil: true,
+		},
+		"enabled": {
+			config: Config{TLS: true},
+		},
+		"server name": {
+			config:     Config{TLSServerName: "redis.local"},
+			wantServer: "redis.local",
+		},
+		"missing ca": {
+			config:  Config{TLSCA: filepath.Join(t.TempDir(), "missing.pem")},
+			wantErr: "read ca",
+		},
+		"invalid ca": {
+			config:  Config{TLSCA: invalidCA},
+			wantErr: "no certificate in ca",
+		},
+		"missing key": {
+			config:  Config{TLSCert: invalidCA},
+			wantErr: "load client certificate",
+		},
+		"missing cert": {
+			config:  Config{TLSKey: invalidCA},
+			wantErr: "load client certificate",
+		},
+	}
+
+	for intention, testCase := range cases {
+		t.Run(intention, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newTLSConfig(&testCase.config)
+
+			if len(testCase.wantErr) != 0 {
+				assert.ErrorContains(t, err, testCase.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if testCase.wantNil {
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.Equal(t, testCase.wantServer, got.ServerName)
+		})
+	}
+}